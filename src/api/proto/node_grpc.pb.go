@@ -21,13 +21,19 @@ const _ = grpc.SupportPackageIsVersion9
 const (
 	NodeService_Ping_FullMethodName              = "/NodeService/Ping"
 	NodeService_GetNextHop_FullMethodName        = "/NodeService/GetNextHop"
+	NodeService_TraceRoute_FullMethodName        = "/NodeService/TraceRoute"
 	NodeService_GetRoutingTable_FullMethodName   = "/NodeService/GetRoutingTable"
+	NodeService_GetLevelNeighbors_FullMethodName = "/NodeService/GetLevelNeighbors"
 	NodeService_AddBackpointer_FullMethodName    = "/NodeService/AddBackpointer"
 	NodeService_RemoveBackpointer_FullMethodName = "/NodeService/RemoveBackpointer"
 	NodeService_NotifyMulticast_FullMethodName   = "/NodeService/NotifyMulticast"
 	NodeService_Publish_FullMethodName           = "/NodeService/Publish"
+	NodeService_Unpublish_FullMethodName         = "/NodeService/Unpublish"
 	NodeService_Lookup_FullMethodName            = "/NodeService/Lookup"
+	NodeService_TransferPointers_FullMethodName  = "/NodeService/TransferPointers"
 	NodeService_Fetch_FullMethodName             = "/NodeService/Fetch"
+	NodeService_FetchStream_FullMethodName       = "/NodeService/FetchStream"
+	NodeService_AntiEntropy_FullMethodName       = "/NodeService/AntiEntropy"
 	NodeService_Replicate_FullMethodName         = "/NodeService/Replicate"
 	NodeService_NotifyLeave_FullMethodName       = "/NodeService/NotifyLeave"
 )
@@ -39,19 +45,25 @@ type NodeServiceClient interface {
 	Ping(ctx context.Context, in *Nothing, opts ...grpc.CallOption) (*Nothing, error)
 	// Core Routing
 	GetNextHop(ctx context.Context, in *RouteRequest, opts ...grpc.CallOption) (*RouteResponse, error)
+	TraceRoute(ctx context.Context, in *TraceRequest, opts ...grpc.CallOption) (*TraceResponse, error)
 	// Bootstrap & Maintenance
 	GetRoutingTable(ctx context.Context, in *Nothing, opts ...grpc.CallOption) (*RTCopyResponse, error)
+	GetLevelNeighbors(ctx context.Context, in *LevelRequest, opts ...grpc.CallOption) (*NeighborList, error)
 	AddBackpointer(ctx context.Context, in *BackpointerRequest, opts ...grpc.CallOption) (*Nothing, error)
 	RemoveBackpointer(ctx context.Context, in *Neighbor, opts ...grpc.CallOption) (*Nothing, error)
-	NotifyMulticast(ctx context.Context, in *MulticastRequest, opts ...grpc.CallOption) (*Nothing, error)
+	NotifyMulticast(ctx context.Context, in *MulticastRequest, opts ...grpc.CallOption) (*MulticastResponse, error)
 	// DOLR
 	Publish(ctx context.Context, in *PublishRequest, opts ...grpc.CallOption) (*Nothing, error)
+	Unpublish(ctx context.Context, in *PublishRequest, opts ...grpc.CallOption) (*Nothing, error)
 	Lookup(ctx context.Context, in *LookupRequest, opts ...grpc.CallOption) (*LookupResponse, error)
+	TransferPointers(ctx context.Context, in *PointerTransfer, opts ...grpc.CallOption) (*Ack, error)
 	// Data Retrieval
 	Fetch(ctx context.Context, in *FetchRequest, opts ...grpc.CallOption) (*FetchResponse, error)
-	// Replication
+	FetchStream(ctx context.Context, in *FetchRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[FetchResponse], error)
+	AntiEntropy(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[MerkleSync, MerkleSync], error)
+	//Replication
 	Replicate(ctx context.Context, in *ReplicateRequest, opts ...grpc.CallOption) (*Ack, error)
-	// Graceful Exit
+	//Graceful Exit
 	NotifyLeave(ctx context.Context, in *Neighbor, opts ...grpc.CallOption) (*Nothing, error)
 }
 
@@ -83,6 +95,16 @@ func (c *nodeServiceClient) GetNextHop(ctx context.Context, in *RouteRequest, op
 	return out, nil
 }
 
+func (c *nodeServiceClient) TraceRoute(ctx context.Context, in *TraceRequest, opts ...grpc.CallOption) (*TraceResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(TraceResponse)
+	err := c.cc.Invoke(ctx, NodeService_TraceRoute_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *nodeServiceClient) GetRoutingTable(ctx context.Context, in *Nothing, opts ...grpc.CallOption) (*RTCopyResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(RTCopyResponse)
@@ -93,6 +115,16 @@ func (c *nodeServiceClient) GetRoutingTable(ctx context.Context, in *Nothing, op
 	return out, nil
 }
 
+func (c *nodeServiceClient) GetLevelNeighbors(ctx context.Context, in *LevelRequest, opts ...grpc.CallOption) (*NeighborList, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(NeighborList)
+	err := c.cc.Invoke(ctx, NodeService_GetLevelNeighbors_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *nodeServiceClient) AddBackpointer(ctx context.Context, in *BackpointerRequest, opts ...grpc.CallOption) (*Nothing, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(Nothing)
@@ -113,9 +145,9 @@ func (c *nodeServiceClient) RemoveBackpointer(ctx context.Context, in *Neighbor,
 	return out, nil
 }
 
-func (c *nodeServiceClient) NotifyMulticast(ctx context.Context, in *MulticastRequest, opts ...grpc.CallOption) (*Nothing, error) {
+func (c *nodeServiceClient) NotifyMulticast(ctx context.Context, in *MulticastRequest, opts ...grpc.CallOption) (*MulticastResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
-	out := new(Nothing)
+	out := new(MulticastResponse)
 	err := c.cc.Invoke(ctx, NodeService_NotifyMulticast_FullMethodName, in, out, cOpts...)
 	if err != nil {
 		return nil, err
@@ -133,6 +165,16 @@ func (c *nodeServiceClient) Publish(ctx context.Context, in *PublishRequest, opt
 	return out, nil
 }
 
+func (c *nodeServiceClient) Unpublish(ctx context.Context, in *PublishRequest, opts ...grpc.CallOption) (*Nothing, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Nothing)
+	err := c.cc.Invoke(ctx, NodeService_Unpublish_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *nodeServiceClient) Lookup(ctx context.Context, in *LookupRequest, opts ...grpc.CallOption) (*LookupResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(LookupResponse)
@@ -143,6 +185,16 @@ func (c *nodeServiceClient) Lookup(ctx context.Context, in *LookupRequest, opts
 	return out, nil
 }
 
+func (c *nodeServiceClient) TransferPointers(ctx context.Context, in *PointerTransfer, opts ...grpc.CallOption) (*Ack, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Ack)
+	err := c.cc.Invoke(ctx, NodeService_TransferPointers_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *nodeServiceClient) Fetch(ctx context.Context, in *FetchRequest, opts ...grpc.CallOption) (*FetchResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(FetchResponse)
@@ -153,6 +205,38 @@ func (c *nodeServiceClient) Fetch(ctx context.Context, in *FetchRequest, opts ..
 	return out, nil
 }
 
+func (c *nodeServiceClient) FetchStream(ctx context.Context, in *FetchRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[FetchResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &NodeService_ServiceDesc.Streams[0], NodeService_FetchStream_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[FetchRequest, FetchResponse]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type NodeService_FetchStreamClient = grpc.ServerStreamingClient[FetchResponse]
+
+func (c *nodeServiceClient) AntiEntropy(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[MerkleSync, MerkleSync], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &NodeService_ServiceDesc.Streams[1], NodeService_AntiEntropy_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[MerkleSync, MerkleSync]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type NodeService_AntiEntropyClient = grpc.BidiStreamingClient[MerkleSync, MerkleSync]
+
 func (c *nodeServiceClient) Replicate(ctx context.Context, in *ReplicateRequest, opts ...grpc.CallOption) (*Ack, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(Ack)
@@ -180,19 +264,25 @@ type NodeServiceServer interface {
 	Ping(context.Context, *Nothing) (*Nothing, error)
 	// Core Routing
 	GetNextHop(context.Context, *RouteRequest) (*RouteResponse, error)
+	TraceRoute(context.Context, *TraceRequest) (*TraceResponse, error)
 	// Bootstrap & Maintenance
 	GetRoutingTable(context.Context, *Nothing) (*RTCopyResponse, error)
+	GetLevelNeighbors(context.Context, *LevelRequest) (*NeighborList, error)
 	AddBackpointer(context.Context, *BackpointerRequest) (*Nothing, error)
 	RemoveBackpointer(context.Context, *Neighbor) (*Nothing, error)
-	NotifyMulticast(context.Context, *MulticastRequest) (*Nothing, error)
+	NotifyMulticast(context.Context, *MulticastRequest) (*MulticastResponse, error)
 	// DOLR
 	Publish(context.Context, *PublishRequest) (*Nothing, error)
+	Unpublish(context.Context, *PublishRequest) (*Nothing, error)
 	Lookup(context.Context, *LookupRequest) (*LookupResponse, error)
+	TransferPointers(context.Context, *PointerTransfer) (*Ack, error)
 	// Data Retrieval
 	Fetch(context.Context, *FetchRequest) (*FetchResponse, error)
-	// Replication
+	FetchStream(*FetchRequest, grpc.ServerStreamingServer[FetchResponse]) error
+	AntiEntropy(grpc.BidiStreamingServer[MerkleSync, MerkleSync]) error
+	//Replication
 	Replicate(context.Context, *ReplicateRequest) (*Ack, error)
-	// Graceful Exit
+	//Graceful Exit
 	NotifyLeave(context.Context, *Neighbor) (*Nothing, error)
 	mustEmbedUnimplementedNodeServiceServer()
 }
@@ -210,27 +300,45 @@ func (UnimplementedNodeServiceServer) Ping(context.Context, *Nothing) (*Nothing,
 func (UnimplementedNodeServiceServer) GetNextHop(context.Context, *RouteRequest) (*RouteResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetNextHop not implemented")
 }
+func (UnimplementedNodeServiceServer) TraceRoute(context.Context, *TraceRequest) (*TraceResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method TraceRoute not implemented")
+}
 func (UnimplementedNodeServiceServer) GetRoutingTable(context.Context, *Nothing) (*RTCopyResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetRoutingTable not implemented")
 }
+func (UnimplementedNodeServiceServer) GetLevelNeighbors(context.Context, *LevelRequest) (*NeighborList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetLevelNeighbors not implemented")
+}
 func (UnimplementedNodeServiceServer) AddBackpointer(context.Context, *BackpointerRequest) (*Nothing, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method AddBackpointer not implemented")
 }
 func (UnimplementedNodeServiceServer) RemoveBackpointer(context.Context, *Neighbor) (*Nothing, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method RemoveBackpointer not implemented")
 }
-func (UnimplementedNodeServiceServer) NotifyMulticast(context.Context, *MulticastRequest) (*Nothing, error) {
+func (UnimplementedNodeServiceServer) NotifyMulticast(context.Context, *MulticastRequest) (*MulticastResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method NotifyMulticast not implemented")
 }
 func (UnimplementedNodeServiceServer) Publish(context.Context, *PublishRequest) (*Nothing, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Publish not implemented")
 }
+func (UnimplementedNodeServiceServer) Unpublish(context.Context, *PublishRequest) (*Nothing, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Unpublish not implemented")
+}
 func (UnimplementedNodeServiceServer) Lookup(context.Context, *LookupRequest) (*LookupResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Lookup not implemented")
 }
+func (UnimplementedNodeServiceServer) TransferPointers(context.Context, *PointerTransfer) (*Ack, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method TransferPointers not implemented")
+}
 func (UnimplementedNodeServiceServer) Fetch(context.Context, *FetchRequest) (*FetchResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Fetch not implemented")
 }
+func (UnimplementedNodeServiceServer) FetchStream(*FetchRequest, grpc.ServerStreamingServer[FetchResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method FetchStream not implemented")
+}
+func (UnimplementedNodeServiceServer) AntiEntropy(grpc.BidiStreamingServer[MerkleSync, MerkleSync]) error {
+	return status.Errorf(codes.Unimplemented, "method AntiEntropy not implemented")
+}
 func (UnimplementedNodeServiceServer) Replicate(context.Context, *ReplicateRequest) (*Ack, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Replicate not implemented")
 }
@@ -294,6 +402,24 @@ func _NodeService_GetNextHop_Handler(srv interface{}, ctx context.Context, dec f
 	return interceptor(ctx, in, info, handler)
 }
 
+func _NodeService_TraceRoute_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TraceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NodeServiceServer).TraceRoute(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NodeService_TraceRoute_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NodeServiceServer).TraceRoute(ctx, req.(*TraceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _NodeService_GetRoutingTable_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(Nothing)
 	if err := dec(in); err != nil {
@@ -312,6 +438,24 @@ func _NodeService_GetRoutingTable_Handler(srv interface{}, ctx context.Context,
 	return interceptor(ctx, in, info, handler)
 }
 
+func _NodeService_GetLevelNeighbors_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LevelRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NodeServiceServer).GetLevelNeighbors(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NodeService_GetLevelNeighbors_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NodeServiceServer).GetLevelNeighbors(ctx, req.(*LevelRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _NodeService_AddBackpointer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(BackpointerRequest)
 	if err := dec(in); err != nil {
@@ -384,6 +528,24 @@ func _NodeService_Publish_Handler(srv interface{}, ctx context.Context, dec func
 	return interceptor(ctx, in, info, handler)
 }
 
+func _NodeService_Unpublish_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PublishRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NodeServiceServer).Unpublish(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NodeService_Unpublish_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NodeServiceServer).Unpublish(ctx, req.(*PublishRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _NodeService_Lookup_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(LookupRequest)
 	if err := dec(in); err != nil {
@@ -402,6 +564,24 @@ func _NodeService_Lookup_Handler(srv interface{}, ctx context.Context, dec func(
 	return interceptor(ctx, in, info, handler)
 }
 
+func _NodeService_TransferPointers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PointerTransfer)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NodeServiceServer).TransferPointers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NodeService_TransferPointers_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NodeServiceServer).TransferPointers(ctx, req.(*PointerTransfer))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _NodeService_Fetch_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(FetchRequest)
 	if err := dec(in); err != nil {
@@ -420,6 +600,24 @@ func _NodeService_Fetch_Handler(srv interface{}, ctx context.Context, dec func(i
 	return interceptor(ctx, in, info, handler)
 }
 
+func _NodeService_FetchStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(FetchRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(NodeServiceServer).FetchStream(m, &grpc.GenericServerStream[FetchRequest, FetchResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type NodeService_FetchStreamServer = grpc.ServerStreamingServer[FetchResponse]
+
+func _NodeService_AntiEntropy_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(NodeServiceServer).AntiEntropy(&grpc.GenericServerStream[MerkleSync, MerkleSync]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type NodeService_AntiEntropyServer = grpc.BidiStreamingServer[MerkleSync, MerkleSync]
+
 func _NodeService_Replicate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(ReplicateRequest)
 	if err := dec(in); err != nil {
@@ -471,10 +669,18 @@ var NodeService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetNextHop",
 			Handler:    _NodeService_GetNextHop_Handler,
 		},
+		{
+			MethodName: "TraceRoute",
+			Handler:    _NodeService_TraceRoute_Handler,
+		},
 		{
 			MethodName: "GetRoutingTable",
 			Handler:    _NodeService_GetRoutingTable_Handler,
 		},
+		{
+			MethodName: "GetLevelNeighbors",
+			Handler:    _NodeService_GetLevelNeighbors_Handler,
+		},
 		{
 			MethodName: "AddBackpointer",
 			Handler:    _NodeService_AddBackpointer_Handler,
@@ -491,10 +697,18 @@ var NodeService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "Publish",
 			Handler:    _NodeService_Publish_Handler,
 		},
+		{
+			MethodName: "Unpublish",
+			Handler:    _NodeService_Unpublish_Handler,
+		},
 		{
 			MethodName: "Lookup",
 			Handler:    _NodeService_Lookup_Handler,
 		},
+		{
+			MethodName: "TransferPointers",
+			Handler:    _NodeService_TransferPointers_Handler,
+		},
 		{
 			MethodName: "Fetch",
 			Handler:    _NodeService_Fetch_Handler,
@@ -508,6 +722,18 @@ var NodeService_ServiceDesc = grpc.ServiceDesc{
 			Handler:    _NodeService_NotifyLeave_Handler,
 		},
 	},
-	Streams:  []grpc.StreamDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "FetchStream",
+			Handler:       _NodeService_FetchStream_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "AntiEntropy",
+			Handler:       _NodeService_AntiEntropy_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+	},
 	Metadata: "api/proto/node.proto",
 }
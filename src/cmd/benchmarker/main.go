@@ -10,6 +10,7 @@ import (
 	"math/rand"
 	"os"
 	"sort"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -43,6 +44,9 @@ type JSONReport struct {
 	ChurnTotal   int `json:"churn_total"`
 
 	ReplicationDelays []float64 `json:"replication_delays"`
+
+	RootChecks     int `json:"root_checks"`
+	RootConsistent int `json:"root_consistent"`
 }
 
 type Benchmarker struct {
@@ -52,10 +56,11 @@ type Benchmarker struct {
 var resultFile *os.File
 
 func main() {
-	mode := flag.String("mode", "hops", "Mode: hops, load, perf, churn, repl")
+	mode := flag.String("mode", "hops", "Mode: hops, load, perf, churn, repl, roots")
 	nodeCount := flag.Int("nodes", 20, "Number of nodes")
 	reqCount := flag.Int("requests", 1000, "Number of requests")
 	concurrency := flag.Int("workers", 10, "Workers")
+	addrs := flag.String("addrs", "", "Comma-separated addresses of a running cluster to verify (roots mode)")
 	flag.Parse()
 
 	var err error
@@ -68,13 +73,19 @@ func main() {
 	report(fmt.Sprintf("=== Tapestry Benchmark Report [%s] ===", time.Now().Format(time.RFC3339)))
 	report(fmt.Sprintf("Mode: %s | Nodes: %d | Requests: %d", *mode, *nodeCount, *reqCount))
 
-	bm := setupCluster(*nodeCount)
-	defer bm.teardown()
-
 	reportData := JSONReport{
 		Mode: *mode, Nodes: *nodeCount, Requests: *reqCount, Workers: *concurrency,
 	}
 
+	if *mode == "roots" && *addrs != "" {
+		reportData.RootChecks, reportData.RootConsistent = verifyRoots(strings.Split(*addrs, ","), *reqCount)
+		writeReport(reportData)
+		return
+	}
+
+	bm := setupCluster(*nodeCount)
+	defer bm.teardown()
+
 	switch *mode {
 	case "hops":
 		reportData.HopCounts, reportData.AvgHops = bm.measureHops(*reqCount)
@@ -86,8 +97,18 @@ func main() {
 		reportData.ChurnSuccess, reportData.ChurnTotal = bm.measureChurn(*reqCount, *concurrency)
 	case "repl":
 		reportData.ReplicationDelays = bm.measureReplication(*reqCount)
+	case "roots":
+		var clusterAddrs []string
+		for _, n := range bm.nodes {
+			clusterAddrs = append(clusterAddrs, n.Address)
+		}
+		reportData.RootChecks, reportData.RootConsistent = verifyRoots(clusterAddrs, *reqCount)
 	}
 
+	writeReport(reportData)
+}
+
+func writeReport(reportData JSONReport) {
 	file, _ := os.Create("results.json")
 	defer file.Close()
 	encoder := json.NewEncoder(file)
@@ -161,35 +182,31 @@ func (bm *Benchmarker) measureHops(samples int) ([]int, float64) {
 }
 
 func traceRoute(startNode *node.Node, targetID id.ID) int {
-	hops := 0
-	currentAddr := startNode.Address
-	currentID := startNode.ID
-	prevPrefixLen := id.SharedPrefixLength(currentID, targetID)
-
-	for hops < 20 {
-		client, err := node.GetClient(currentAddr)
-		if err != nil {
-			return hops
-		}
-		req := &pb.RouteRequest{TargetId: &pb.NodeID{Bytes: targetID.Bytes()}}
-		resp, err := client.GetNextHop(context.Background(), req)
-		client.Close()
-		if err != nil || resp.IsRoot || resp.NextHop.Address == currentAddr {
-			return hops
-		}
-
-		var nextID id.ID
-		copy(nextID[:], resp.NextHop.Id.Bytes)
-		currPrefixLen := id.SharedPrefixLength(nextID, targetID)
+	req := &pb.TraceRequest{
+		TargetId: &pb.NodeID{Bytes: targetID.Bytes()},
+		HopLimit: node.MAX_HOPS,
+	}
+	resp, err := startNode.TraceRoute(context.Background(), req)
+	if err != nil || len(resp.Hops) == 0 {
+		return 0
+	}
+	return len(resp.Hops) - 1
+}
 
-		if currPrefixLen < prevPrefixLen {
-			// violation
+func verifyRoots(addrs []string, samples int) (int, int) {
+	report("--- Root Uniqueness Results ---")
+	consistent := 0
+	for i := 0; i < samples; i++ {
+		target := id.NewRandomID()
+		r := node.VerifyRoot(addrs, target)
+		if r.Unique() {
+			consistent++
+		} else {
+			report(fmt.Sprintf("Target %s: %d distinct roots, %d failed traces", target, len(r.Roots), r.Failed))
 		}
-		prevPrefixLen = currPrefixLen
-		currentAddr = resp.NextHop.Address
-		hops++
 	}
-	return hops
+	report(fmt.Sprintf("Consistent Roots: %d/%d", consistent, samples))
+	return samples, consistent
 }
 
 func (bm *Benchmarker) measureLoadBalance(objects int) (map[string]int, float64, float64, float64) {
@@ -198,7 +215,7 @@ func (bm *Benchmarker) measureLoadBalance(objects int) (map[string]int, float64,
 	for i := 0; i < objects; i++ {
 		src := bm.nodes[rand.Intn(len(bm.nodes))]
 		key := fmt.Sprintf("obj-%d", i)
-		go src.StoreAndPublish(key, "data")
+		go src.StoreAndPublish(key, []byte("data"))
 		if i%100 == 0 { time.Sleep(10 * time.Millisecond) }
 	}
 	
@@ -275,7 +292,7 @@ func (bm *Benchmarker) measurePerformance(requests int, workers int) ([]float64,
 				key := fmt.Sprintf("perf-%d-%d", rand.Int(), i)
 				t0 := time.Now()
 				if i%5 == 0 {
-					n.StoreAndPublish(key, "data")
+					n.StoreAndPublish(key, []byte("data"))
 					putLats <- float64(time.Since(t0).Microseconds()) / 1000.0
 				} else {
 					n.Get("some-key")
@@ -366,7 +383,7 @@ func (bm *Benchmarker) measureChurn(requests int, workers int) (int, int) {
 
 				key := "resilient-data"
 				if i == 0 {
-					bm.nodes[0].StoreAndPublish(key, "data")
+					bm.nodes[0].StoreAndPublish(key, []byte("data"))
 				}
 
 				_, err := n.Get(key)
@@ -395,7 +412,7 @@ func (bm *Benchmarker) measureReplication(samples int) []float64 {
 		key := fmt.Sprintf("repl-%d", i)
 		primary := bm.nodes[0]
 		t0 := time.Now()
-		primary.StoreAndPublish(key, "data")
+		primary.StoreAndPublish(key, []byte("data"))
 		finder := bm.nodes[len(bm.nodes)-1]
 		for {
 			if _, err := finder.Get(key); err == nil {
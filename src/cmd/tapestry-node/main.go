@@ -1,29 +1,143 @@
 package main
 
 import (
+	"crypto/ed25519"
 	"flag"
 	"fmt"
 	"log"
+	"net"
 	"os"
 	"os/signal"
+	"path/filepath"
+	"strconv"
 	"strings"
 	"syscall"
 	"time"
 
+	"tapestry/internal/id"
 	"tapestry/internal/node"
 )
 
 func main() {
 	portPtr := flag.Int("port", 0, "gRPC port for the node.")
 	httpPortPtr := flag.Int("httpport", 0, "HTTP port for the node's API.")
-	bootPtr := flag.String("boot", "", "Comma-separated list of bootstrap ports.")
+	bootPtr := flag.String("boot", "", "Comma-separated list of bootstrap addresses (host:port, or a bare port on localhost).")
+	listenPtr := flag.String("listen", "", "host:port to bind the gRPC server to (default :<port>).")
+	advertisePtr := flag.String("advertise", "", "host:port other nodes use to reach this node (default: the listen address).")
+	storePtr := flag.String("store", "memory", "Object store backend: memory or disk.")
+	dataDirPtr := flag.String("data-dir", "", "Directory for persistent node data (default data/node-<port>).")
+	idPtr := flag.String("id", "", "Expected hex node ID. The ID is derived from the key in the data directory; startup fails if they differ.")
+	showIDPtr := flag.Bool("show-id", false, "Print the node ID for the key in the data directory and exit.")
+	writeQuorumPtr := flag.Int("w", node.DEFAULT_WRITE_QUORUM, "Copies a put must store before returning.")
+	readQuorumPtr := flag.Int("r", node.DEFAULT_READ_QUORUM, "Copies a get must consult.")
+	readRepairPtr := flag.Bool("read-repair", false, "Refresh lagging replicas on every get.")
+	tombstoneGracePtr := flag.Duration("tombstone-grace", node.TOMBSTONE_GRACE_PERIOD, "How long a delete is remembered before its tombstone is collected.")
+	placementPtr := flag.String("placement", "salted-root", "Replica placement policy: salted-root or random.")
+	aeIntervalPtr := flag.Duration("anti-entropy-interval", node.ANTI_ENTROPY_INTERVAL, "How often replicas are reconciled.")
+	aeBandwidthPtr := flag.Int64("anti-entropy-bandwidth", node.ANTI_ENTROPY_BANDWIDTH, "Anti-entropy bandwidth cap in bytes per second (negative for unlimited).")
+	maxConnsPtr := flag.Int("max-conns", node.CONN_POOL_MAX_SIZE, "Maximum number of pooled outgoing connections.")
+	connIdlePtr := flag.Duration("conn-idle-timeout", node.CONN_IDLE_TIMEOUT, "Close outgoing connections unused for this long.")
+	rpcTimeoutPtr := flag.Duration("rpc-timeout", node.DEFAULT_RPC_TIMEOUT, "Deadline for a single call to a peer.")
+	routeTimeoutPtr := flag.Duration("route-timeout", node.DEFAULT_ROUTE_TIMEOUT, "Deadline for a message routed across the overlay.")
+	getTimeoutPtr := flag.Duration("get-timeout", node.DEFAULT_GET_TIMEOUT, "Deadline for a whole get.")
+	putTimeoutPtr := flag.Duration("put-timeout", node.DEFAULT_PUT_TIMEOUT, "Deadline for a whole put or delete.")
+	maxUploadPtr := flag.Int64("max-upload", node.DEFAULT_MAX_UPLOAD_SIZE, "Largest value in bytes the HTTP API accepts for a publish.")
+	tlsCertPtr := flag.String("tls-cert", "", "PEM certificate for mutual TLS; its common name must be the node ID.")
+	tlsKeyPtr := flag.String("tls-key", "", "PEM private key for -tls-cert.")
+	tlsCAPtr := flag.String("tls-ca", "", "PEM CA bundle that signs every node certificate.")
 	flag.Parse()
 
-	if *portPtr == 0 {
-		log.Fatal("Port is required")
+	port := *portPtr
+	if *listenPtr != "" {
+		_, p, err := net.SplitHostPort(*listenPtr)
+		if err != nil {
+			log.Fatalf("Invalid listen address %q: %v", *listenPtr, err)
+		}
+		port, _ = strconv.Atoi(p)
+	}
+	if port == 0 {
+		log.Fatal("Port is required (-port or -listen)")
+	}
+
+	dataDir := *dataDirPtr
+	if dataDir == "" {
+		dataDir = filepath.Join("data", fmt.Sprintf("node-%d", port))
+	}
+
+	cfg := node.Config{
+		Port:                 port,
+		ListenAddr:           *listenPtr,
+		AdvertiseAddr:        *advertisePtr,
+		WriteQuorum:          *writeQuorumPtr,
+		ReadQuorum:           *readQuorumPtr,
+		ReadRepair:           *readRepairPtr,
+		TombstoneGrace:       *tombstoneGracePtr,
+		AntiEntropyInterval:  *aeIntervalPtr,
+		AntiEntropyBandwidth: *aeBandwidthPtr,
+		MaxConnections:       *maxConnsPtr,
+		ConnIdleTimeout:      *connIdlePtr,
+		MaxUploadSize:        *maxUploadPtr,
+		Timeouts: node.Timeouts{
+			RPC:   *rpcTimeoutPtr,
+			Route: *routeTimeoutPtr,
+			Get:   *getTimeoutPtr,
+			Put:   *putTimeoutPtr,
+		},
+	}
+
+	useTLS := *tlsCertPtr != "" || *tlsKeyPtr != "" || *tlsCAPtr != ""
+	if useTLS {
+		if *tlsCertPtr == "" || *tlsKeyPtr == "" || *tlsCAPtr == "" {
+			log.Fatal("-tls-cert, -tls-key and -tls-ca must be given together")
+		}
+		tlsConfig, err := node.LoadTLSConfig(*tlsCertPtr, *tlsKeyPtr, *tlsCAPtr)
+		if err != nil {
+			log.Fatalf("Failed to load TLS configuration: %v", err)
+		}
+		cfg.TLS = tlsConfig
+	}
+
+	// A certificate names a node ID, so TLS needs the key to survive restarts.
+	persistent := *dataDirPtr != "" || *idPtr != "" || *storePtr == "disk" || useTLS || *showIDPtr
+	if persistent {
+		key, err := node.LoadOrCreateKey(dataDir)
+		if err != nil {
+			log.Fatalf("Failed to load node key: %v", err)
+		}
+		if *showIDPtr {
+			fmt.Println(node.KeyID(key.Public().(ed25519.PublicKey)))
+			return
+		}
+		cfg.Key = key
+		cfg.DataDir = dataDir
+	}
+	if *idPtr != "" {
+		expected, err := id.Parse(*idPtr)
+		if err != nil {
+			log.Fatalf("Invalid node ID %q: %v", *idPtr, err)
+		}
+		cfg.ID = expected
 	}
 
-	n, err := node.NewNode(*portPtr)
+	placement, err := node.PlacementByName(*placementPtr)
+	if err != nil {
+		log.Fatal(err)
+	}
+	cfg.Placement = placement
+
+	switch *storePtr {
+	case "memory":
+	case "disk":
+		store, err := node.OpenLogStore(dataDir)
+		if err != nil {
+			log.Fatalf("Failed to open disk store: %v", err)
+		}
+		cfg.Store = store
+	default:
+		log.Fatalf("Unknown store backend %q", *storePtr)
+	}
+
+	n, err := node.NewNodeWithConfig(cfg)
 	if err != nil {
 		log.Fatalf("Failed to create node: %v", err)
 	}
@@ -34,21 +148,38 @@ func main() {
 
 	go n.StartHttpServer(*httpPortPtr)
 
-	if *bootPtr != "" {
-		time.Sleep(1 * time.Second) 
-		
-		ports := strings.Split(*bootPtr, ",")
-		var bootstrapAddrs []string
-		for _, p := range ports {
-			if p != "" {
-				bootstrapAddrs = append(bootstrapAddrs, fmt.Sprintf("localhost:%s", p))
-			}
+	var bootstrapAddrs []string
+	for _, b := range strings.Split(*bootPtr, ",") {
+		b = strings.TrimSpace(b)
+		if b == "" {
+			continue
 		}
+		if !strings.Contains(b, ":") {
+			b = fmt.Sprintf("localhost:%s", b)
+		}
+		bootstrapAddrs = append(bootstrapAddrs, b)
+	}
+
+	var savedAddrs []string
+	if persistent {
+		saved, err := node.LoadRoutingTable(dataDir)
+		if err != nil {
+			log.Printf("Ignoring saved routing table: %v", err)
+		}
+		for _, nb := range saved {
+			savedAddrs = append(savedAddrs, nb.Address)
+		}
+	}
+
+	if len(bootstrapAddrs) > 0 || len(savedAddrs) > 0 {
+		time.Sleep(1 * time.Second) 
 
-		if len(bootstrapAddrs) > 0 {
-			if err := n.Join(bootstrapAddrs); err != nil {
+		if err := n.Join(append(bootstrapAddrs, savedAddrs...)); err != nil {
+			if len(bootstrapAddrs) > 0 {
 				log.Fatalf("Failed to join network: %v", err)
 			}
+			log.Printf("None of the %d saved neighbors answered. Starting as standalone (Genesis node).", len(savedAddrs))
+		} else {
 			log.Printf("Node %s successfully joined network.", n.ID)
 		}
 	} else {
@@ -0,0 +1,323 @@
+package node
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"sync"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+
+	pb "tapestry/api/proto"
+	"tapestry/internal/id"
+)
+
+const (
+	ANTI_ENTROPY_INTERVAL  = 45 * time.Second
+	ANTI_ENTROPY_BANDWIDTH = 1 << 20 // bytes per second
+	AE_MAX_BATCH_BYTES     = 2 << 20 // Objects per message, well under gRPC's 4MB default
+)
+
+const (
+	MERKLE_LEVEL_ROOT = iota
+	MERKLE_LEVEL_INNER
+	MERKLE_LEVEL_LEAF
+	MERKLE_LEVEL_KEYS
+	MERKLE_LEVEL_OBJECTS
+)
+
+// byteLimiter paces anti-entropy traffic to a fixed number of bytes per
+// second. A zero rate disables it.
+type byteLimiter struct {
+	rate int64
+	next time.Time
+	lock sync.Mutex
+}
+
+func (l *byteLimiter) wait(size int) {
+	if l == nil || l.rate <= 0 {
+		return
+	}
+	l.lock.Lock()
+	now := time.Now()
+	if l.next.Before(now) {
+		l.next = now
+	}
+	delay := l.next.Sub(now)
+	l.next = l.next.Add(time.Duration(int64(size) * int64(time.Second) / l.rate))
+	l.lock.Unlock()
+
+	time.Sleep(delay)
+}
+
+func (n *Node) StartAntiEntropyLoop() {
+	ticker := time.NewTicker(n.AntiEntropyInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-n.stopChan:
+			return
+		case <-ticker.C:
+			n.RunAntiEntropy()
+		}
+	}
+}
+
+// RunAntiEntropy compares the objects this node owns with every known
+// replica holder and repairs the keys that differ on either side. It
+// returns the number of keys repaired.
+func (n *Node) RunAntiEntropy() int {
+	perPeer := make(map[string][]Object)
+	peers := make(map[string]Neighbor)
+	for _, obj := range n.Objects.List() {
+		if obj.Owner != n.ID.String() {
+			continue
+		}
+		for _, h := range n.holdersOf(obj.Key) {
+			peers[h.ID.String()] = h
+			perPeer[h.ID.String()] = append(perPeer[h.ID.String()], obj)
+		}
+	}
+
+	repaired := 0
+	for key, peer := range peers {
+		count, err := n.syncWith(peer, perPeer[key])
+		if err != nil {
+			log.Printf("[ANTI-ENTROPY] Sync with %s failed: %v", peer.Address, err)
+			continue
+		}
+		repaired += count
+	}
+
+	if repaired > 0 {
+		log.Printf("[ANTI-ENTROPY] Repaired %d keys across %d peers", repaired, len(peers))
+	}
+	return repaired
+}
+
+func (n *Node) syncWith(peer Neighbor, objects []Object) (int, error) {
+	client, err := n.getClient(peer)
+	if err != nil {
+		return 0, err
+	}
+	defer client.Close()
+
+	// A sync that has not finished by the next round is abandoned.
+	ctx, cancel := withBudget(context.Background(), n.AntiEntropyInterval)
+	defer cancel()
+	stream, err := client.AntiEntropy(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer stream.CloseSend()
+
+	exchange := func(msg *pb.MerkleSync) (*pb.MerkleSync, error) {
+		msg.Owner = n.ID.String()
+		n.aeLimiter.wait(proto.Size(msg))
+		if err := stream.Send(msg); err != nil {
+			return nil, err
+		}
+		resp, err := stream.Recv()
+		if err != nil {
+			return nil, err
+		}
+		n.aeLimiter.wait(proto.Size(resp))
+		if msg.Level <= MERKLE_LEVEL_LEAF && len(resp.Hashes) != len(msg.Indices) {
+			return nil, fmt.Errorf("peer answered %d hashes for %d nodes", len(resp.Hashes), len(msg.Indices))
+		}
+		return resp, nil
+	}
+
+	tree := buildMerkleTree(objects)
+
+	resp, err := exchange(&pb.MerkleSync{Level: MERKLE_LEVEL_ROOT, Indices: []int32{0}})
+	if err != nil {
+		return 0, err
+	}
+	if bytes.Equal(resp.Hashes[0], tree.root) {
+		return 0, nil
+	}
+
+	var inner []int32
+	for i := 0; i < MERKLE_FANOUT; i++ {
+		inner = append(inner, int32(i))
+	}
+	resp, err = exchange(&pb.MerkleSync{Level: MERKLE_LEVEL_INNER, Indices: inner})
+	if err != nil {
+		return 0, err
+	}
+
+	var leaves []int32
+	for i, idx := range inner {
+		if bytes.Equal(resp.Hashes[i], tree.inner[idx]) {
+			continue
+		}
+		for j := 0; j < MERKLE_FANOUT; j++ {
+			leaves = append(leaves, idx*MERKLE_FANOUT+int32(j))
+		}
+	}
+	resp, err = exchange(&pb.MerkleSync{Level: MERKLE_LEVEL_LEAF, Indices: leaves})
+	if err != nil {
+		return 0, err
+	}
+
+	var buckets []int32
+	for i, idx := range leaves {
+		if !bytes.Equal(resp.Hashes[i], tree.leaves[idx]) {
+			buckets = append(buckets, idx)
+		}
+	}
+	resp, err = exchange(&pb.MerkleSync{Level: MERKLE_LEVEL_KEYS, Indices: buckets})
+	if err != nil {
+		return 0, err
+	}
+
+	remote := make(map[string][]byte)
+	for _, kd := range resp.Keys {
+		remote[kd.Key] = kd.Digest
+	}
+
+	var pushed []*pb.ReplicateRequest
+	for _, b := range buckets {
+		for key, digest := range tree.buckets[b] {
+			if !bytes.Equal(remote[key], digest) {
+				if obj, ok := n.Objects.Get(id.Hash(key)); ok {
+					pushed = append(pushed, obj.toReplicateRequest())
+				}
+			}
+		}
+	}
+	var want []string
+	for key, digest := range remote {
+		if local, ok := tree.buckets[merkleBucket(key)][key]; !ok || !bytes.Equal(local, digest) {
+			want = append(want, key)
+		}
+	}
+
+	repaired := make(map[string]bool)
+	for _, req := range pushed {
+		repaired[req.Key] = true
+	}
+	for _, key := range want {
+		repaired[key] = true
+	}
+
+	// Objects cross in batches below the message size limit. The peer answers
+	// as many wanted keys as fit and hands the rest back for the next round.
+	for len(pushed) > 0 || len(want) > 0 {
+		push := &pb.MerkleSync{Level: MERKLE_LEVEL_OBJECTS, Want: want}
+		size := 0
+		for len(pushed) > 0 {
+			next := proto.Size(pushed[0])
+			if len(push.Objects) > 0 && size+next > AE_MAX_BATCH_BYTES {
+				break
+			}
+			size += next
+			push.Objects = append(push.Objects, pushed[0])
+			pushed = pushed[1:]
+		}
+
+		resp, err = exchange(push)
+		if err != nil {
+			return 0, err
+		}
+		if len(want) > 0 && len(resp.Want) >= len(want) {
+			return 0, fmt.Errorf("peer deferred all %d wanted keys", len(want))
+		}
+		want = resp.Want
+
+		for _, req := range resp.Objects {
+			if err := n.storeLocal(objectFromProto(req)); err != nil {
+				log.Printf("[ANTI-ENTROPY] Failed to store '%s' from %s: %v", req.Key, peer.Address, err)
+				continue
+			}
+			n.trackHolder(req.Key, peer)
+			go n.publishSelfSalted(context.Background(), req.Key)
+		}
+	}
+	return len(repaired), nil
+}
+
+// AntiEntropy answers a peer's Merkle comparison over the objects owned by
+// the peer that this node holds.
+func (n *Node) AntiEntropy(stream pb.NodeService_AntiEntropyServer) error {
+	var tree *merkleTree
+	for {
+		msg, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		if tree == nil {
+			var owned []Object
+			for _, obj := range n.Objects.List() {
+				if obj.Owner == msg.Owner {
+					owned = append(owned, obj)
+				}
+			}
+			tree = buildMerkleTree(owned)
+		}
+
+		resp := &pb.MerkleSync{Owner: msg.Owner, Level: msg.Level, Indices: msg.Indices}
+		for _, idx := range msg.Indices {
+			limit := int32(MERKLE_LEAVES)
+			switch msg.Level {
+			case MERKLE_LEVEL_ROOT:
+				limit = 1
+			case MERKLE_LEVEL_INNER:
+				limit = MERKLE_FANOUT
+			}
+			if idx < 0 || idx >= limit {
+				return fmt.Errorf("index %d out of range at level %d", idx, msg.Level)
+			}
+
+			switch msg.Level {
+			case MERKLE_LEVEL_ROOT:
+				resp.Hashes = append(resp.Hashes, tree.root)
+			case MERKLE_LEVEL_INNER:
+				resp.Hashes = append(resp.Hashes, tree.inner[idx])
+			case MERKLE_LEVEL_LEAF:
+				resp.Hashes = append(resp.Hashes, tree.leaves[idx])
+			case MERKLE_LEVEL_KEYS:
+				for key, digest := range tree.buckets[idx] {
+					resp.Keys = append(resp.Keys, &pb.KeyDigest{Key: key, Digest: digest})
+				}
+			}
+		}
+
+		if msg.Level == MERKLE_LEVEL_OBJECTS {
+			for _, req := range msg.Objects {
+				if err := n.storeLocal(objectFromProto(req)); err != nil {
+					return err
+				}
+				go n.publishSelfSalted(context.Background(), req.Key)
+			}
+			size := 0
+			for i, key := range msg.Want {
+				obj, ok := n.Objects.Get(id.Hash(key))
+				if !ok {
+					continue
+				}
+				req := obj.toReplicateRequest()
+				if len(resp.Objects) > 0 && size+proto.Size(req) > AE_MAX_BATCH_BYTES {
+					resp.Want = msg.Want[i:]
+					break
+				}
+				size += proto.Size(req)
+				resp.Objects = append(resp.Objects, req)
+			}
+			log.Printf("[ANTI-ENTROPY] Received %d and sent %d objects for %s", len(msg.Objects), len(resp.Objects), msg.Owner)
+		}
+
+		if err := stream.Send(resp); err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,164 @@
+package node
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+)
+
+// appendLog is a file of newline-separated JSON records, the format shared
+// by LogStore and HintQueue. Each record is synced before append returns,
+// and a torn record at the end is cut off on replay.
+type appendLog struct {
+	path   string
+	prefix string // Log prefix of the owner, e.g. "[STORE]"
+	file   *os.File
+	writer *bufio.Writer
+}
+
+// openAppendLog hands every record in path to apply, in order, and opens the
+// file for appending.
+func openAppendLog(path, prefix string, apply func(json.RawMessage)) (*appendLog, error) {
+	l := &appendLog{path: path, prefix: prefix}
+	if err := l.replay(apply); err != nil {
+		return nil, err
+	}
+	if err := l.open(); err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+func (l *appendLog) replay(apply func(json.RawMessage)) error {
+	file, err := os.Open(l.path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to open log %s: %w", l.path, err)
+	}
+	defer file.Close()
+
+	decoder := json.NewDecoder(bufio.NewReader(file))
+	var good int64 // End of the last record that decoded cleanly
+	for {
+		var raw json.RawMessage
+		if err := decoder.Decode(&raw); err != nil {
+			if err != io.EOF {
+				log.Printf("%s Dropping truncated tail of %s: %v", l.prefix, l.path, err)
+				return truncateTail(l.path, good)
+			}
+			return nil
+		}
+		good = decoder.InputOffset()
+		apply(raw)
+	}
+}
+
+func (l *appendLog) open() error {
+	file, err := os.OpenFile(l.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open log %s: %w", l.path, err)
+	}
+	l.file = file
+	l.writer = bufio.NewWriter(file)
+	return nil
+}
+
+// truncateTail cuts a torn record off the end of a log so the next append
+// starts on a fresh line instead of being glued onto the garbage.
+func truncateTail(path string, size int64) error {
+	if err := os.Truncate(path, size); err != nil {
+		return fmt.Errorf("failed to truncate log %s: %w", path, err)
+	}
+	if size == 0 {
+		return nil
+	}
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open log %s: %w", path, err)
+	}
+	defer file.Close()
+	if _, err := file.Write([]byte{'\n'}); err != nil {
+		return fmt.Errorf("failed to truncate log %s: %w", path, err)
+	}
+	return file.Sync()
+}
+
+func (l *appendLog) append(rec any) error {
+	if l.file == nil {
+		return fmt.Errorf("log %s is closed", l.path)
+	}
+	data, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+	if _, err := l.writer.Write(append(data, '\n')); err != nil {
+		return err
+	}
+	if err := l.writer.Flush(); err != nil {
+		return err
+	}
+	return l.file.Sync()
+}
+
+// needsCompaction reports whether stale records are both numerous and
+// outnumber the live ones.
+func needsCompaction(stale, live int) bool {
+	return stale >= COMPACT_MIN_RECORDS && stale >= live
+}
+
+// rewrite replaces the log with the records emit writes and atomically swaps
+// it in. The log stays open for appending even if the swap fails.
+func (l *appendLog) rewrite(emit func(write func(rec any) error) error) error {
+	tmpPath := l.path + ".tmp"
+	tmp, err := os.Create(tmpPath)
+	if err != nil {
+		return fmt.Errorf("failed to create compaction file: %w", err)
+	}
+
+	w := bufio.NewWriter(tmp)
+	err = emit(func(rec any) error {
+		data, err := json.Marshal(rec)
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(append(data, '\n'))
+		return err
+	})
+	if err == nil {
+		err = w.Flush()
+	}
+	if err == nil {
+		err = tmp.Sync()
+	}
+	tmp.Close()
+	if err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+
+	l.close()
+	renameErr := os.Rename(tmpPath, l.path)
+	if err := l.open(); err != nil {
+		return fmt.Errorf("failed to reopen log: %w", err)
+	}
+	if renameErr != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to swap compacted log: %w", renameErr)
+	}
+	return nil
+}
+
+func (l *appendLog) close() error {
+	if l.file == nil {
+		return nil
+	}
+	l.writer.Flush()
+	err := l.file.Close()
+	l.file = nil
+	return err
+}
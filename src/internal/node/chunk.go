@@ -0,0 +1,282 @@
+package node
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log"
+	"strings"
+	"time"
+
+	pb "tapestry/api/proto"
+	"tapestry/internal/id"
+)
+
+const (
+	CHUNK_SIZE         = 256 * 1024
+	FETCH_FRAME_SIZE   = 64 * 1024
+	BLOCK_KEY_PREFIX   = "block:"
+	BLOCK_GRACE_PERIOD = 10 * time.Minute // Lets a manifest still being written land before its blocks are checked
+)
+
+func blockKey(chunk []byte) string {
+	sum := sha1.Sum(chunk)
+	return BLOCK_KEY_PREFIX + hex.EncodeToString(sum[:])
+}
+
+// storeChunked splits data into content-addressed blocks, publishes each one
+// as its own object and stores a manifest listing them under key. Each block
+// records key as a back-reference, since other manifests may share it.
+func (n *Node) storeChunked(ctx context.Context, key string, data []byte, expiresAt time.Time, w int) error {
+	var blocks []string
+	now := time.Now()
+	for off := 0; off < len(data); off += CHUNK_SIZE {
+		chunk := data[off:min(off+CHUNK_SIZE, len(data))]
+		bk := blockKey(chunk)
+		block := Object{Key: bk, Data: chunk, Size: int64(len(chunk)), ExpiresAt: expiresAt, Refs: map[string]time.Time{key: now}}
+		if err := n.storeAndReplicate(ctx, block, w); err != nil {
+			return fmt.Errorf("failed to store block %d of '%s': %w", len(blocks), key, err)
+		}
+		blocks = append(blocks, bk)
+	}
+
+	log.Printf("[CHUNK] Split '%s' (%d bytes) into %d blocks", key, len(data), len(blocks))
+	return n.storeAndReplicate(ctx, Object{Key: key, Chunks: blocks, Size: int64(len(data)), ExpiresAt: expiresAt}, w)
+}
+
+// assemble reads the blocks listed in manifest. Blocks are verified against
+// their key, so any single copy is enough to read one.
+func (n *Node) assemble(ctx context.Context, manifest Object) (Object, error) {
+	data := make([]byte, 0, manifest.Size)
+	for _, bk := range manifest.Chunks {
+		block, err := n.getObject(ctx, bk, 1, false)
+		if err != nil {
+			return Object{}, fmt.Errorf("missing block %s of '%s': %w", bk, manifest.Key, err)
+		}
+		if blockKey(block.Data) != bk {
+			return Object{}, fmt.Errorf("block %s of '%s' failed verification", bk, manifest.Key)
+		}
+		data = append(data, block.Data...)
+	}
+
+	if int64(len(data)) != manifest.Size {
+		return Object{}, fmt.Errorf("reassembled '%s' is %d bytes, expected %d", manifest.Key, len(data), manifest.Size)
+	}
+	manifest.Data = data
+	return manifest, nil
+}
+
+// CollectBlocks drops local blocks that no manifest lists any more. A block
+// may be shared by manifests stored anywhere in the mesh, so it is only
+// dropped once every manifest in its back-references has been read and
+// found not to list it; a manifest that cannot be read keeps the block. It
+// returns the number collected.
+func (n *Node) CollectBlocks() int {
+	listed := make(map[string]map[string]bool) // manifest key -> blocks it lists
+	collected := 0
+	for _, obj := range n.Objects.List() {
+		if !strings.HasPrefix(obj.Key, BLOCK_KEY_PREFIX) || obj.Deleted || len(obj.Refs) == 0 {
+			continue
+		}
+		if time.Since(newestRef(obj.Refs)) < n.BlockGrace {
+			continue
+		}
+
+		referenced := false
+		for manifest := range obj.Refs {
+			blocks, checked := listed[manifest]
+			if !checked {
+				blocks = n.manifestBlocks(manifest)
+				listed[manifest] = blocks
+			}
+			if blocks == nil || blocks[obj.Key] {
+				referenced = true
+				break
+			}
+		}
+		if !referenced && n.dropBlock(obj) {
+			collected++
+		}
+	}
+	if collected > 0 {
+		log.Printf("[CHUNK] Collected %d unreferenced blocks", collected)
+	}
+	return collected
+}
+
+// manifestBlocks returns the blocks listed by any live version of the
+// manifest stored under key, or nil if no copy of it could be read.
+func (n *Node) manifestBlocks(key string) map[string]bool {
+	ctx, cancel := withBudget(context.Background(), n.Timeouts.Get)
+	defer cancel()
+	manifest, err := n.getObject(ctx, key, 0, false)
+	if err != nil {
+		return nil
+	}
+	blocks := make(map[string]bool)
+	for _, v := range manifest.versions() {
+		if v.Deleted {
+			continue
+		}
+		for _, bk := range v.Chunks {
+			blocks[bk] = true
+		}
+	}
+	return blocks
+}
+
+// dropBlock removes block unless a manifest referenced it again while its
+// back-references were being checked.
+func (n *Node) dropBlock(block Object) bool {
+	n.storeLock.Lock()
+	// A new back-reference adds a manifest or moves the newest time forward.
+	current, ok := n.Objects.Get(id.Hash(block.Key))
+	if !ok || !newestRef(current.Refs).Equal(newestRef(block.Refs)) || len(current.Refs) != len(block.Refs) {
+		n.storeLock.Unlock()
+		return false
+	}
+	if err := n.Objects.Delete(id.Hash(block.Key)); err != nil {
+		n.storeLock.Unlock()
+		log.Printf("[CHUNK] Failed to delete block %s: %v", block.Key, err)
+		return false
+	}
+	n.storeLock.Unlock()
+
+	n.forgetHolders(block.Key)
+	n.unpublishSelfSalted(context.Background(), block.Key)
+	return true
+}
+
+// mergeRefs unions two sets of back-references, keeping the latest time each
+// manifest listed the block.
+func mergeRefs(a, b map[string]time.Time) map[string]time.Time {
+	if len(a) == 0 && len(b) == 0 {
+		return nil
+	}
+	merged := make(map[string]time.Time, len(a)+len(b))
+	for key, at := range a {
+		merged[key] = at
+	}
+	for key, at := range b {
+		if at.After(merged[key]) {
+			merged[key] = at
+		}
+	}
+	return merged
+}
+
+func newestRef(refs map[string]time.Time) time.Time {
+	var newest time.Time
+	for _, at := range refs {
+		if at.After(newest) {
+			newest = at
+		}
+	}
+	return newest
+}
+
+func refsToProto(refs map[string]time.Time) map[string]int64 {
+	if len(refs) == 0 {
+		return nil
+	}
+	out := make(map[string]int64, len(refs))
+	for key, at := range refs {
+		out[key] = toUnixNanos(at)
+	}
+	return out
+}
+
+func refsFromProto(refs map[string]int64) map[string]time.Time {
+	if len(refs) == 0 {
+		return nil
+	}
+	out := make(map[string]time.Time, len(refs))
+	for key, ns := range refs {
+		out[key] = unixNanos(ns)
+	}
+	return out
+}
+
+// FetchStream sends an object in frames of at most FETCH_FRAME_SIZE bytes.
+// The first frame carries the metadata.
+func (n *Node) FetchStream(req *pb.FetchRequest, stream pb.NodeService_FetchStreamServer) error {
+	resp, err := n.Fetch(stream.Context(), req)
+	if err != nil {
+		return err
+	}
+	if !resp.Found {
+		return stream.Send(resp)
+	}
+
+	data := resp.Data
+	first := true
+	for first || len(data) > 0 {
+		frame := data[:min(FETCH_FRAME_SIZE, len(data))]
+		data = data[len(frame):]
+
+		msg := &pb.FetchResponse{Data: frame}
+		if first {
+			msg.Found = true
+			msg.Chunks = resp.Chunks
+			msg.Size = resp.Size
+			msg.Version = resp.Version
+			msg.Siblings = resp.Siblings
+			msg.Owner = resp.Owner
+			msg.Deleted = resp.Deleted
+			msg.DeletedAt = resp.DeletedAt
+			msg.ExpiresAt = resp.ExpiresAt
+			msg.Refs = resp.Refs
+			first = false
+		}
+		if err := stream.Send(msg); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func fetchStream(ctx context.Context, client *TapestryClient, key string) (Object, bool, error) {
+	stream, err := client.FetchStream(ctx, &pb.FetchRequest{Key: key})
+	if err != nil {
+		return Object{}, false, err
+	}
+
+	first, err := stream.Recv()
+	if err != nil {
+		return Object{}, false, err
+	}
+	if !first.Found {
+		return Object{}, false, nil
+	}
+
+	obj := Object{
+		Key:       key,
+		Data:      first.Data,
+		Chunks:    first.Chunks,
+		Size:      first.Size,
+		Version:   versionFromProto(first.Version),
+		Siblings:  siblingsFromProto(key, first.Siblings),
+		Owner:     first.Owner,
+		Deleted:   first.Deleted,
+		DeletedAt: unixNanos(first.DeletedAt),
+		ExpiresAt: unixNanos(first.ExpiresAt),
+		Refs:      refsFromProto(first.Refs),
+	}
+	for {
+		msg, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return Object{}, false, err
+		}
+		obj.Data = append(obj.Data, msg.Data...)
+	}
+
+	if len(obj.Chunks) == 0 && int64(len(obj.Data)) != obj.Size {
+		return Object{}, false, fmt.Errorf("short read for '%s': got %d of %d bytes", key, len(obj.Data), obj.Size)
+	}
+	return obj, true, nil
+}
@@ -2,80 +2,287 @@ package node
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"log"
 	"sync"
 	"time"
 
 	pb "tapestry/api/proto"
+	"tapestry/internal/id"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
 	"google.golang.org/grpc/connectivity"
+	"google.golang.org/grpc/credentials"
+)
+
+const (
+	CONN_POOL_MAX_SIZE  = 256
+	CONN_IDLE_TIMEOUT   = 5 * time.Minute
+	CONN_EVICT_INTERVAL = 30 * time.Second
 )
 
 var (
-	connPool = make(map[string]*grpc.ClientConn)
-	poolLock sync.RWMutex
+	ErrPoolClosed    = errors.New("connection pool is closed")
+	ErrPoolExhausted = errors.New("connection pool is full and every connection is in use")
 )
 
+// The pool behind GetClient, for callers that are not a node.
+var defaultPool = NewConnPool(ConnPoolConfig{})
+
+type ConnPoolConfig struct {
+	MaxSize     int           // Defaults to CONN_POOL_MAX_SIZE
+	IdleTimeout time.Duration // Unused connections are closed after this; defaults to CONN_IDLE_TIMEOUT
+	Creds       credentials.TransportCredentials // Nil uses whatever SetClientTLS installed
+	OnTransientFailure func(address string) // Called when a connection enters TRANSIENT_FAILURE
+}
+
+// ConnPool shares one gRPC connection per address and expected node ID
+// between callers. Clients hold a reference until Close; connections nobody
+// holds are closed once idle, or earlier when the pool is full.
+type ConnPool struct {
+	cfg    ConnPoolConfig
+	conns  map[string]*pooledConn
+	closed bool
+	lock   sync.Mutex
+	stop   chan struct{}
+}
+
+type pooledConn struct {
+	address  string
+	conn     *grpc.ClientConn
+	refs     int
+	lastUsed time.Time
+	retired  bool // Replaced after a failure; closed once the last holder releases it
+}
+
 type TapestryClient struct {
 	pb.NodeServiceClient
-	connAddr string 
+	connAddr string
+	pool     *ConnPool
+	pc       *pooledConn
+	once     sync.Once
+}
+
+func NewConnPool(cfg ConnPoolConfig) *ConnPool {
+	if cfg.MaxSize <= 0 {
+		cfg.MaxSize = CONN_POOL_MAX_SIZE
+	}
+	if cfg.IdleTimeout <= 0 {
+		cfg.IdleTimeout = CONN_IDLE_TIMEOUT
+	}
+	p := &ConnPool{
+		cfg:   cfg,
+		conns: make(map[string]*pooledConn),
+		stop:  make(chan struct{}),
+	}
+	go p.evictLoop()
+	return p
 }
 
 func GetClient(address string) (*TapestryClient, error) {
-	poolLock.RLock()
-	conn, exists := connPool[address]
-	poolLock.RUnlock()
+	return defaultPool.Get(address)
+}
+
+// Get returns a client for address without checking which node answers.
+func (p *ConnPool) Get(address string) (*TapestryClient, error) {
+	return p.GetNode(address, id.ZeroID)
+}
 
+// GetNode returns a client for the node nodeID at address. Over TLS the
+// handshake fails unless the server's certificate names nodeID; a zero ID
+// only checks the chain. Dialing does not block: connection errors surface
+// on the first RPC. A connection that has failed is replaced
+// rather than left to gRPC's reconnect backoff, so a peer that comes back is
+// reachable on the next call. Callers still holding the failed connection
+// keep it until they release it.
+func (p *ConnPool) GetNode(address string, nodeID id.ID) (*TapestryClient, error) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	if p.closed {
+		return nil, ErrPoolClosed
+	}
+
+	key := address
+	opts := []grpc.DialOption{}
+	if !nodeID.Equals(id.ZeroID) {
+		// The TLS server name carries the expected ID to clientTLSConfig.
+		key = nodeID.String() + "@" + address
+		opts = append(opts, grpc.WithAuthority(nodeID.String()))
+	}
+
+	pc, exists := p.conns[key]
 	if exists {
-		state := conn.GetState()
-		if state != connectivity.Shutdown {
-			return &TapestryClient{
-				NodeServiceClient: pb.NewNodeServiceClient(conn),
-				connAddr:          address,
-			}, nil
+		switch pc.conn.GetState() {
+		case connectivity.TransientFailure:
+			if pc.refs > 0 {
+				pc.retired = true
+			} else {
+				pc.conn.Close()
+			}
+			fallthrough
+		case connectivity.Shutdown:
+			delete(p.conns, key)
+			exists = false
+		}
+	}
+	if !exists {
+		if len(p.conns) >= p.cfg.MaxSize && !p.evictOldestLocked() {
+			return nil, ErrPoolExhausted
+		}
+		creds := p.cfg.Creds
+		if creds == nil {
+			creds = clientCreds
 		}
+		conn, err := grpc.NewClient(address, append(opts, grpc.WithTransportCredentials(creds))...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to connect to %s: %w", address, err)
+		}
+		pc = &pooledConn{address: address, conn: conn}
+		p.conns[key] = pc
+		go p.watch(address, conn)
 	}
 
-	poolLock.Lock()
-	defer poolLock.Unlock()
+	pc.refs++
+	pc.lastUsed = time.Now()
+	return &TapestryClient{
+		NodeServiceClient: pb.NewNodeServiceClient(pc.conn),
+		connAddr:          address,
+		pool:              p,
+		pc:                pc,
+	}, nil
+}
 
-	if conn, exists = connPool[address]; exists {
-		if conn.GetState() != connectivity.Shutdown {
-			return &TapestryClient{
-				NodeServiceClient: pb.NewNodeServiceClient(conn),
-				connAddr:          address,
-			}, nil
+// Close hands the connection back to the pool. It is safe to call twice.
+func (c *TapestryClient) Close() {
+	if c.pool == nil {
+		return
+	}
+	c.once.Do(func() {
+		c.pool.release(c.pc)
+	})
+}
+
+func (p *ConnPool) release(pc *pooledConn) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if pc.refs > 0 {
+		pc.refs--
+		pc.lastUsed = time.Now()
+	}
+	if pc.retired && pc.refs == 0 {
+		pc.conn.Close()
+	}
+}
+
+// Remove closes the connections to address, e.g. because the node left.
+// Clients still holding them see their RPCs fail.
+func (p *ConnPool) Remove(address string) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	for key, pc := range p.conns {
+		if pc.address == address {
+			pc.conn.Close()
+			delete(p.conns, key)
 		}
 	}
+}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
+func (p *ConnPool) Len() int {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	return len(p.conns)
+}
 
-	newConn, err := grpc.DialContext(ctx, address,
-		grpc.WithTransportCredentials(insecure.NewCredentials()),
-		grpc.WithBlock(),
-	)
-	if err != nil {
-		return nil, fmt.Errorf("failed to connect to %s: %w", address, err)
+// evictOldestLocked closes the least recently used connection nobody holds.
+// Callers must hold the lock.
+func (p *ConnPool) evictOldestLocked() bool {
+	oldest := ""
+	for addr, pc := range p.conns {
+		if pc.refs > 0 {
+			continue
+		}
+		if oldest == "" || pc.lastUsed.Before(p.conns[oldest].lastUsed) {
+			oldest = addr
+		}
+	}
+	if oldest == "" {
+		return false
 	}
+	p.conns[oldest].conn.Close()
+	delete(p.conns, oldest)
+	return true
+}
 
-	connPool[address] = newConn
+// EvictIdle closes unused connections idle for longer than the idle timeout
+// and returns how many were closed.
+func (p *ConnPool) EvictIdle() int {
+	p.lock.Lock()
+	defer p.lock.Unlock()
 
-	return &TapestryClient{
-		NodeServiceClient: pb.NewNodeServiceClient(newConn),
-		connAddr:          address,
-	}, nil
+	evicted := 0
+	for addr, pc := range p.conns {
+		if pc.refs == 0 && time.Since(pc.lastUsed) > p.cfg.IdleTimeout {
+			pc.conn.Close()
+			delete(p.conns, addr)
+			evicted++
+		}
+	}
+	return evicted
 }
 
-func (c *TapestryClient) Close() {
+func (p *ConnPool) evictLoop() {
+	ticker := time.NewTicker(CONN_EVICT_INTERVAL)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-p.stop:
+			return
+		case <-ticker.C:
+			if evicted := p.EvictIdle(); evicted > 0 {
+				log.Printf("[POOL] Closed %d idle connections", evicted)
+			}
+		}
+	}
 }
 
-func CloseAllConnections() {
-	poolLock.Lock()
-	defer poolLock.Unlock()
-	for addr, conn := range connPool {
-		conn.Close()
-		delete(connPool, addr)
+func (p *ConnPool) watch(address string, conn *grpc.ClientConn) {
+	state := conn.GetState()
+	for conn.WaitForStateChange(context.Background(), state) {
+		state = conn.GetState()
+		switch state {
+		case connectivity.Shutdown:
+			return
+		case connectivity.TransientFailure:
+			if p.cfg.OnTransientFailure != nil {
+				p.cfg.OnTransientFailure(address)
+			}
+		}
+	}
+}
+
+// CloseAll closes every connection but leaves the pool usable.
+func (p *ConnPool) CloseAll() {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	for addr, pc := range p.conns {
+		pc.conn.Close()
+		delete(p.conns, addr)
+	}
+}
+
+// Close closes every connection and stops the pool.
+func (p *ConnPool) Close() {
+	p.lock.Lock()
+	if !p.closed {
+		p.closed = true
+		close(p.stop)
 	}
-}
\ No newline at end of file
+	p.lock.Unlock()
+	p.CloseAll()
+}
+
+func CloseAllConnections() {
+	defaultPool.CloseAll()
+}
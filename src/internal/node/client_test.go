@@ -0,0 +1,94 @@
+package node
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/connectivity"
+)
+
+func TestConnPoolLimits(t *testing.T) {
+	p := NewConnPool(ConnPoolConfig{MaxSize: 2, IdleTimeout: time.Hour})
+	defer p.Close()
+
+	a, err := p.Get("localhost:1")
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	shared, _ := p.Get("localhost:1")
+	b, _ := p.Get("localhost:2")
+	if p.Len() != 2 {
+		t.Fatalf("Expected the connection to be shared, pool has %d", p.Len())
+	}
+
+	if _, err := p.Get("localhost:3"); err != ErrPoolExhausted {
+		t.Errorf("Expected ErrPoolExhausted while every connection is held, got %v", err)
+	}
+
+	a.Close()
+	a.Close()
+	if _, err := p.Get("localhost:3"); err != ErrPoolExhausted {
+		t.Errorf("A double Close released a connection that is still held, got %v", err)
+	}
+
+	shared.Close()
+	c, err := p.Get("localhost:3")
+	if err != nil {
+		t.Fatalf("Idle connection was not evicted to make room: %v", err)
+	}
+	if p.Len() != 2 {
+		t.Errorf("Expected 2 pooled connections, got %d", p.Len())
+	}
+	b.Close()
+	c.Close()
+
+	p.Close()
+	if _, err := p.Get("localhost:1"); err != ErrPoolClosed {
+		t.Errorf("Expected ErrPoolClosed, got %v", err)
+	}
+}
+
+func TestConnPoolEvictIdle(t *testing.T) {
+	p := NewConnPool(ConnPoolConfig{IdleTimeout: time.Millisecond})
+	defer p.Close()
+
+	held, _ := p.Get("localhost:1")
+	released, _ := p.Get("localhost:2")
+	released.Close()
+	time.Sleep(5 * time.Millisecond)
+
+	if evicted := p.EvictIdle(); evicted != 1 {
+		t.Errorf("Expected 1 idle connection evicted, got %d", evicted)
+	}
+	held.Close()
+}
+
+func TestConnPoolDrainsFailedConnection(t *testing.T) {
+	p := NewConnPool(ConnPoolConfig{IdleTimeout: time.Hour})
+	defer p.Close()
+
+	held, _ := p.Get("localhost:1")
+	conn := held.pc.conn
+	conn.Connect()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	for state := conn.GetState(); state != connectivity.TransientFailure; state = conn.GetState() {
+		if !conn.WaitForStateChange(ctx, state) {
+			t.Fatalf("Connection never failed, stuck in %v", state)
+		}
+	}
+
+	fresh, _ := p.Get("localhost:1")
+	if fresh.pc == held.pc {
+		t.Fatalf("Failed connection was handed out again")
+	}
+	if conn.GetState() == connectivity.Shutdown {
+		t.Errorf("Failed connection was closed while still held")
+	}
+	held.Close()
+	if conn.GetState() != connectivity.Shutdown {
+		t.Errorf("Failed connection was not closed once released")
+	}
+	fresh.Close()
+}
@@ -2,6 +2,7 @@ package node
 
 import (
 	"context"
+	"fmt"
 	"log"
 	"time" 
 
@@ -12,6 +13,9 @@ import (
 const MAX_HOPS = 20
 
 func (n *Node) Publish(ctx context.Context, req *pb.PublishRequest) (*pb.Nothing, error) {
+	if req.ObjectId == nil || len(req.ObjectId.Bytes) != id.BYTES {
+		return nil, fmt.Errorf("invalid object ID length")
+	}
 	var objectID id.ID
 	copy(objectID[:], req.ObjectId.Bytes)
 
@@ -19,6 +23,10 @@ func (n *Node) Publish(ctx context.Context, req *pb.PublishRequest) (*pb.Nothing
 	if err != nil {
 		return nil, err
 	}
+	if err := verifyPublish(SIGN_PUBLISH, objectID, publisher, req.Timestamp, req.Signature); err != nil {
+		log.Printf("Node %s rejected Publish for %s: %v", n.ID, objectID, err)
+		return nil, err
+	}
 
 	if req.HopLimit <= 0 {
 		if req.HopLimit == 0 {
@@ -28,24 +36,72 @@ func (n *Node) Publish(ctx context.Context, req *pb.PublishRequest) (*pb.Nothing
 
 	log.Printf("Node %s handling Publish for %s (Hops Left: %d)", n.ID, objectID, req.HopLimit)
 
-	n.addLocationPointer(objectID, publisher)
-
-	nextHop, isRoot := n.computeNextHop(objectID)
+	if !n.addLocationPointer(objectID, publisher, req.Signature, req.Timestamp) {
+		log.Printf("Node %s rejected Publish for %s by %s: %v", n.ID, objectID, publisher.ID, ErrStalePublish)
+		return nil, fmt.Errorf("publish of %s by %s: %w", objectID, publisher.ID, ErrStalePublish)
+	}
 
-	if isRoot || nextHop.ID.Equals(n.ID) || req.HopLimit <= 1 {
-		log.Printf("Node %s terminating Publish for %s (Root=%v, Limit=%d)", n.ID, objectID, isRoot, req.HopLimit)
+	if req.HopLimit <= 1 {
+		log.Printf("Node %s terminating Publish for %s (Limit=%d)", n.ID, objectID, req.HopLimit)
 		return &pb.Nothing{}, nil
 	}
 
-	client, err := GetClient(nextHop.Address)
+	req.HopLimit--
+	d, err := n.forward(ctx, objectID, func(ctx context.Context, client *TapestryClient, d routeDecision) error {
+		_, err := client.Publish(ctx, req)
+		return err
+	})
 	if err != nil {
-		log.Printf("Failed to forward Publish to %s: %v", nextHop.Address, err)
+		log.Printf("Failed to forward Publish for %s: %v", objectID, err)
 		return nil, err
 	}
-	defer client.Close()
+	if d.IsRoot {
+		log.Printf("Node %s terminating Publish for %s (Root=true)", n.ID, objectID)
+	}
+	return &pb.Nothing{}, nil
+}
+
+func (n *Node) Unpublish(ctx context.Context, req *pb.PublishRequest) (*pb.Nothing, error) {
+	if req.ObjectId == nil || len(req.ObjectId.Bytes) != id.BYTES {
+		return nil, fmt.Errorf("invalid object ID length")
+	}
+	var objectID id.ID
+	copy(objectID[:], req.ObjectId.Bytes)
+
+	publisher, err := NeighborFromProto(req.Publisher)
+	if err != nil {
+		return nil, err
+	}
+	if err := verifyPublish(SIGN_UNPUBLISH, objectID, publisher, req.Timestamp, req.Signature); err != nil {
+		log.Printf("Node %s rejected Unpublish for %s: %v", n.ID, objectID, err)
+		return nil, err
+	}
+
+	if req.HopLimit == 0 {
+		req.HopLimit = MAX_HOPS
+	}
+
+	log.Printf("Node %s handling Unpublish for %s by %s (Hops Left: %d)", n.ID, objectID, publisher.ID, req.HopLimit)
+
+	if !n.removeLocationPointer(objectID, publisher.ID, req.Timestamp) {
+		log.Printf("Node %s rejected Unpublish for %s by %s: %v", n.ID, objectID, publisher.ID, ErrStalePublish)
+		return nil, fmt.Errorf("unpublish of %s by %s: %w", objectID, publisher.ID, ErrStalePublish)
+	}
+
+	if req.HopLimit <= 1 {
+		return &pb.Nothing{}, nil
+	}
 
 	req.HopLimit--
-	return client.Publish(ctx, req)
+	_, err = n.forward(ctx, objectID, func(ctx context.Context, client *TapestryClient, d routeDecision) error {
+		_, err := client.Unpublish(ctx, req)
+		return err
+	})
+	if err != nil {
+		log.Printf("Failed to forward Unpublish for %s: %v", objectID, err)
+		return nil, err
+	}
+	return &pb.Nothing{}, nil
 }
 
 func (n *Node) Lookup(ctx context.Context, req *pb.LookupRequest) (*pb.LookupResponse, error) {
@@ -71,40 +127,228 @@ func (n *Node) Lookup(ctx context.Context, req *pb.LookupRequest) (*pb.LookupRes
 		}, nil
 	}
 
-	nextHop, isRoot := n.computeNextHop(objectID)
-
-	if isRoot || nextHop.ID.Equals(n.ID) || req.HopLimit <= 1 {
+	if req.HopLimit <= 1 {
 		return &pb.LookupResponse{Found: false}, nil
 	}
 
-	client, err := GetClient(nextHop.Address)
+	req.HopLimit--
+	resp := &pb.LookupResponse{Found: false}
+	_, err := n.forward(ctx, objectID, func(ctx context.Context, client *TapestryClient, d routeDecision) error {
+		r, err := client.Lookup(ctx, req)
+		if err == nil {
+			resp = r
+		}
+		return err
+	})
 	if err != nil {
 		return nil, err
 	}
+	return resp, nil
+}
+
+func (n *Node) TransferPointers(ctx context.Context, req *pb.PointerTransfer) (*pb.Ack, error) {
+	if req.HopLimit == 0 {
+		req.HopLimit = MAX_HOPS
+	}
+
+	onward := make(map[string][]*pb.PointerSet)
+	targets := make(map[string]Neighbor)
+	count := 0
+
+	for _, set := range req.Sets {
+		if set.ObjectId == nil || len(set.ObjectId.Bytes) != id.BYTES {
+			log.Printf("Node %s dropped transferred pointer set with invalid object ID", n.ID)
+			continue
+		}
+		var objectID id.ID
+		copy(objectID[:], set.ObjectId.Bytes)
+
+		for i, pubProto := range set.Publishers {
+			publisher, err := NeighborFromProto(pubProto)
+			if err != nil {
+				continue
+			}
+			var sig []byte
+			var stamp int64
+			if i < len(set.Signatures) && i < len(set.Timestamps) {
+				sig, stamp = set.Signatures[i], set.Timestamps[i]
+			}
+			if err := verifyPublish(SIGN_PUBLISH, objectID, publisher, stamp, sig); err != nil {
+				log.Printf("Node %s dropped transferred pointer for %s: %v", n.ID, objectID, err)
+				continue
+			}
+			if n.addLocationPointer(objectID, publisher, sig, stamp) {
+				count++
+			}
+		}
+
+		nextHop, isRoot := n.computeNextHop(objectID)
+		if isRoot || nextHop.ID.Equals(n.ID) || req.HopLimit <= 1 {
+			continue
+		}
+		key := nextHop.ID.String()
+		targets[key] = nextHop
+		onward[key] = append(onward[key], set)
+	}
+
+	log.Printf("Node %s received %d location pointers for %d objects", n.ID, count, len(req.Sets))
+
+	for key, sets := range onward {
+		if err := n.sendPointers(ctx, targets[key], sets, req.HopLimit-1); err != nil {
+			log.Printf("Failed to forward pointers to %s: %v", targets[key].Address, err)
+		}
+	}
+
+	return &pb.Ack{Success: true}, nil
+}
+
+func (n *Node) sendPointers(ctx context.Context, target Neighbor, sets []*pb.PointerSet, hopLimit int32) error {
+	client, err := n.getClient(target)
+	if err != nil {
+		return err
+	}
 	defer client.Close()
 
-	req.HopLimit--
-	return client.Lookup(ctx, req)
+	ctx, cancel := n.routeContext(ctx)
+	defer cancel()
+	_, err = client.TransferPointers(ctx, &pb.PointerTransfer{Sets: sets, HopLimit: hopLimit})
+	return err
 }
 
+// rootedObjects lists the objects we currently hold pointers for as their root.
+func (n *Node) rootedObjects() []id.ID {
+	n.lpLock.RLock()
+	var keys []id.ID
+	for objID := range n.LocationPointers {
+		keys = append(keys, objID)
+	}
+	n.lpLock.RUnlock()
+
+	var rooted []id.ID
+	for _, objID := range keys {
+		if _, isRoot := n.computeNextHop(objID); isRoot {
+			rooted = append(rooted, objID)
+		}
+	}
+	return rooted
+}
+
+// migratePointers pushes the pointers of previously rooted objects to their
+// new root after the routing table changed. It returns how many were moved.
+func (n *Node) migratePointers(ctx context.Context, rooted []id.ID) int {
+	batches := make(map[string][]*pb.PointerSet)
+	targets := make(map[string]Neighbor)
+	moved := 0
+
+	for _, objID := range rooted {
+		nextHop, isRoot := n.computeNextHop(objID)
+		if isRoot || nextHop.ID.Equals(n.ID) {
+			continue
+		}
+
+		set := n.pointerSet(objID)
+		if len(set.Publishers) == 0 {
+			continue
+		}
+
+		key := nextHop.ID.String()
+		targets[key] = nextHop
+		batches[key] = append(batches[key], set)
+		moved += len(set.Publishers)
+	}
+
+	for key, sets := range batches {
+		if err := n.sendPointers(ctx, targets[key], sets, MAX_HOPS); err != nil {
+			log.Printf("Failed to migrate %d pointer sets to %s: %v", len(sets), targets[key].Address, err)
+		} else {
+			log.Printf("Migrated %d pointer sets to new root %s", len(sets), targets[key].ID)
+		}
+	}
+	return moved
+}
 
-func (n *Node) addLocationPointer(objID id.ID, publisher Neighbor) {
+// addLocationPointer records a publish of objID made at stamp. It refuses
+// one older than the publisher's last publish or unpublish of objID.
+func (n *Node) addLocationPointer(objID id.ID, publisher Neighbor, sig []byte, stamp int64) bool {
 	n.lpLock.Lock()
 	defer n.lpLock.Unlock()
 
+	if stamp <= n.withdrawn[objID][publisher.ID.String()] {
+		return false
+	}
+
 	entries := n.LocationPointers[objID]
 	
 	for _, entry := range entries {
 		if entry.Neighbor.ID.Equals(publisher.ID) {
+			if stamp < entry.Timestamp {
+				return false
+			}
+			entry.Signature = sig
+			entry.Timestamp = stamp
 			entry.LastUpdated = time.Now() 
-			return 
+			return true
 		}
 	}
 
 	n.LocationPointers[objID] = append(entries, &PointerEntry{
 		Neighbor:    publisher,
+		Signature:   sig,
+		Timestamp:   stamp,
 		LastUpdated: time.Now(),
 	})
+	return true
+}
+
+// pointerSet packages our pointers for objID together with the publishers'
+// signatures so the receiver can verify them.
+func (n *Node) pointerSet(objID id.ID) *pb.PointerSet {
+	n.lpLock.RLock()
+	defer n.lpLock.RUnlock()
+
+	set := &pb.PointerSet{ObjectId: &pb.NodeID{Bytes: objID.Bytes()}}
+	for _, entry := range n.LocationPointers[objID] {
+		set.Publishers = append(set.Publishers, entry.Neighbor.ToProto())
+		set.Signatures = append(set.Signatures, entry.Signature)
+		set.Timestamps = append(set.Timestamps, entry.Timestamp)
+	}
+	return set
+}
+
+// removeLocationPointer applies an unpublish of objID made at stamp. It
+// refuses one older than the pointer's publish, and remembers the stamp until
+// it expires so that earlier publishes cannot be replayed afterwards.
+func (n *Node) removeLocationPointer(objID id.ID, publisherID id.ID, stamp int64) bool {
+	n.lpLock.Lock()
+	defer n.lpLock.Unlock()
+
+	key := publisherID.String()
+	if stamp <= n.withdrawn[objID][key] {
+		return false
+	}
+
+	entries := n.LocationPointers[objID]
+	for i, entry := range entries {
+		if entry.Neighbor.ID.Equals(publisherID) {
+			if stamp < entry.Timestamp {
+				return false
+			}
+			entries = append(entries[:i], entries[i+1:]...)
+			break
+		}
+	}
+
+	if n.withdrawn[objID] == nil {
+		n.withdrawn[objID] = make(map[string]int64)
+	}
+	n.withdrawn[objID][key] = stamp
+
+	if len(entries) == 0 {
+		delete(n.LocationPointers, objID)
+	} else {
+		n.LocationPointers[objID] = entries
+	}
+	return true
 }
 
 func (n *Node) getLocationPointers(objID id.ID) []Neighbor {
@@ -0,0 +1,27 @@
+package node
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+func (o Object) Expired() bool {
+	return !o.ExpiresAt.IsZero() && time.Now().After(o.ExpiresAt)
+}
+
+// SweepExpired drops every local object whose TTL has passed and stops
+// advertising it. It returns the number removed.
+func (n *Node) SweepExpired() int {
+	swept := 0
+	for _, obj := range n.Objects.List() {
+		if obj.Expired() {
+			n.removeLocal(context.Background(), obj.Key)
+			swept++
+		}
+	}
+	if swept > 0 {
+		log.Printf("[STORE] Swept %d expired objects", swept)
+	}
+	return swept
+}
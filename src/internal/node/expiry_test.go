@@ -0,0 +1,33 @@
+package node
+
+import (
+	"testing"
+	"time"
+
+	"tapestry/internal/id"
+)
+
+func TestSweepExpired(t *testing.T) {
+	localID := id.NewRandomID()
+	n := &Node{
+		ID:             localID,
+		Table:          NewRoutingTable(localID),
+		Objects:        NewMemoryStore(),
+		replicaHolders: make(map[string]map[string]Neighbor),
+	}
+	n.Objects.Put(id.Hash("old"), Object{Key: "old", ExpiresAt: time.Now().Add(-time.Second)})
+	n.Objects.Put(id.Hash("fresh"), Object{Key: "fresh", ExpiresAt: time.Now().Add(time.Hour)})
+	n.Objects.Put(id.Hash("forever"), Object{Key: "forever"})
+
+	if swept := n.SweepExpired(); swept != 1 {
+		t.Errorf("Expected 1 object swept, got %d", swept)
+	}
+	if _, ok := n.Objects.Get(id.Hash("old")); ok {
+		t.Errorf("Expired object was not removed")
+	}
+	for _, key := range []string{"fresh", "forever"} {
+		if _, ok := n.Objects.Get(id.Hash(key)); !ok {
+			t.Errorf("Live object '%s' was removed", key)
+		}
+	}
+}
@@ -0,0 +1,336 @@
+package node
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"tapestry/internal/id"
+)
+
+const (
+	HINT_FILE_NAME = "hints.log"
+	HINT_TTL       = 6 * time.Hour
+)
+
+// Hint is a replica write that failed and is waiting for its target to come
+// back.
+type Hint struct {
+	TargetID   string    `json:"targetId"`
+	TargetAddr string    `json:"targetAddr"`
+	Object     Object    `json:"object"`
+	Created    time.Time `json:"created"`
+}
+
+// hintRecord is one line of the hint log. Only "add" carries a payload;
+// "del" drops one hint and "take" every hint of a target.
+type hintRecord struct {
+	Op     string `json:"op"`
+	Target string `json:"target,omitempty"`
+	Key    string `json:"key,omitempty"`
+	Hint   *Hint  `json:"hint,omitempty"`
+}
+
+// HintQueue holds pending hints per target. When backed by a directory every
+// change is appended to an appendLog, as in LogStore, so hints survive restarts
+// without rewriting every payload on each change.
+type HintQueue struct {
+	log   *appendLog                 // Nil for a queue kept only in memory
+	hints map[string]map[string]Hint // target ID -> key -> hint
+	stale int
+	lock  sync.Mutex
+}
+
+func NewHintQueue(dir string) (*HintQueue, error) {
+	q := &HintQueue{hints: make(map[string]map[string]Hint)}
+	if dir == "" {
+		return q, nil
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create hint directory %s: %w", dir, err)
+	}
+
+	l, err := openAppendLog(filepath.Join(dir, HINT_FILE_NAME), "[HINTS]", func(raw json.RawMessage) {
+		var rec hintRecord
+		if json.Unmarshal(raw, &rec) == nil {
+			q.apply(rec)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	q.log = l
+
+	if pending := q.len(); pending > 0 {
+		log.Printf("[HINTS] Loaded %d pending hints from %s", pending, l.path)
+	}
+	return q, nil
+}
+
+// apply updates the in-memory queue for rec and counts the records it makes
+// stale.
+func (q *HintQueue) apply(rec hintRecord) {
+	switch rec.Op {
+	case "add":
+		if rec.Hint == nil {
+			return
+		}
+		h := *rec.Hint
+		if q.hints[h.TargetID] == nil {
+			q.hints[h.TargetID] = make(map[string]Hint)
+		}
+		if _, exists := q.hints[h.TargetID][h.Object.Key]; exists {
+			q.stale++
+		}
+		q.hints[h.TargetID][h.Object.Key] = h
+	case "del":
+		if _, exists := q.hints[rec.Target][rec.Key]; exists {
+			delete(q.hints[rec.Target], rec.Key)
+			if len(q.hints[rec.Target]) == 0 {
+				delete(q.hints, rec.Target)
+			}
+			q.stale++
+		}
+		q.stale++
+	case "take":
+		q.stale += len(q.hints[rec.Target]) + 1
+		delete(q.hints, rec.Target)
+	}
+}
+
+// record applies rec and appends it to the log. Callers must hold the lock.
+func (q *HintQueue) record(rec hintRecord) error {
+	q.apply(rec)
+	if q.log == nil {
+		return nil
+	}
+	if err := q.log.append(rec); err != nil {
+		return err
+	}
+	if !needsCompaction(q.stale, q.len()) {
+		return nil
+	}
+	return q.compact()
+}
+
+// Add queues obj for target, replacing any older hint for the same key.
+func (q *HintQueue) Add(target Neighbor, obj Object) error {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	h := Hint{TargetID: target.ID.String(), TargetAddr: target.Address, Object: obj, Created: time.Now()}
+	return q.record(hintRecord{Op: "add", Hint: &h})
+}
+
+// Take removes and returns every hint queued for target.
+func (q *HintQueue) Take(targetID string) ([]Hint, error) {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	var taken []Hint
+	for _, h := range q.hints[targetID] {
+		taken = append(taken, h)
+	}
+	if len(taken) == 0 {
+		return nil, nil
+	}
+	return taken, q.record(hintRecord{Op: "take", Target: targetID})
+}
+
+// Requeue puts back hints whose replay failed, keeping their creation time.
+func (q *HintQueue) Requeue(hints []Hint) error {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	for _, h := range hints {
+		h := h
+		if err := q.record(hintRecord{Op: "add", Hint: &h}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Expire drops hints older than ttl and returns how many were dropped.
+func (q *HintQueue) Expire(ttl time.Duration) (int, error) {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	var expired []hintRecord
+	for target, byKey := range q.hints {
+		for key, h := range byKey {
+			if time.Since(h.Created) > ttl {
+				expired = append(expired, hintRecord{Op: "del", Target: target, Key: key})
+			}
+		}
+	}
+	for _, rec := range expired {
+		if err := q.record(rec); err != nil {
+			return len(expired), err
+		}
+	}
+	return len(expired), nil
+}
+
+// Targets returns one entry per node that has hints waiting.
+func (q *HintQueue) Targets() []Neighbor {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	var targets []Neighbor
+	for targetID, byKey := range q.hints {
+		nbID, err := id.Parse(targetID)
+		if err != nil {
+			continue
+		}
+		for _, h := range byKey {
+			targets = append(targets, Neighbor{ID: nbID, Address: h.TargetAddr})
+			break
+		}
+	}
+	return targets
+}
+
+func (q *HintQueue) Len() int {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	return q.len()
+}
+
+func (q *HintQueue) len() int {
+	total := 0
+	for _, byKey := range q.hints {
+		total += len(byKey)
+	}
+	return total
+}
+
+func (q *HintQueue) Close() error {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	if q.log == nil {
+		return nil
+	}
+	return q.log.close()
+}
+
+// compact rewrites the log with one record per pending hint. Callers must
+// hold the lock.
+func (q *HintQueue) compact() error {
+	err := q.log.rewrite(func(write func(rec any) error) error {
+		for _, byKey := range q.hints {
+			for _, h := range byKey {
+				h := h
+				if err := write(hintRecord{Op: "add", Hint: &h}); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	q.stale = 0
+	return nil
+}
+
+func (n *Node) queueHint(target Neighbor, obj Object) {
+	if err := n.hints.Add(target, obj); err != nil {
+		log.Printf("[HINTS] Failed to persist hint for '%s': %v", obj.Key, err)
+	}
+	atomic.AddInt64(&n.Metrics.HintsQueued, 1)
+	log.Printf("[HINTS] Queued '%s' for %s", obj.Key, target.Address)
+}
+
+// replayHints delivers the hints queued for target, which was just seen alive.
+func (n *Node) replayHints(target Neighbor) int {
+	hints, err := n.hints.Take(target.ID.String())
+	if err != nil {
+		log.Printf("[HINTS] Failed to persist hint queue: %v", err)
+	}
+	if len(hints) == 0 {
+		return 0
+	}
+
+	client, err := n.getClient(target)
+	if err != nil {
+		n.hints.Requeue(hints)
+		return 0
+	}
+	defer client.Close()
+
+	var failed []Hint
+	for _, h := range hints {
+		obj := h.Object
+		if current, ok := n.Objects.Get(id.Hash(obj.Key)); ok {
+			obj = resolve(current, obj)
+		}
+		ctx, cancel := n.rpcContext(context.Background())
+		_, err := client.Replicate(ctx, obj.toReplicateRequest())
+		cancel()
+		if err != nil {
+			failed = append(failed, h)
+			continue
+		}
+		if obj.Owner == n.ID.String() {
+			n.trackHolder(obj.Key, target)
+		}
+	}
+	if len(failed) > 0 {
+		n.hints.Requeue(failed)
+	}
+
+	replayed := len(hints) - len(failed)
+	atomic.AddInt64(&n.Metrics.HintsReplayed, int64(replayed))
+	log.Printf("[HINTS] Replayed %d/%d hints to %s", replayed, len(hints), target.Address)
+	return replayed
+}
+
+// ReplayHints probes every node with pending hints and replays those that
+// answer. It returns the number of hints delivered.
+func (n *Node) ReplayHints() int {
+	return n.replayHintsTo(nil)
+}
+
+// replayHintsTo replays the hints of each target once. Targets in alive were
+// just reached by a keepalive and are not probed again.
+func (n *Node) replayHintsTo(alive map[string]bool) int {
+	replayed := 0
+	for _, target := range n.hints.Targets() {
+		if !alive[target.ID.String()] {
+			if _, err := n.probe(context.Background(), target); err != nil {
+				continue
+			}
+		}
+		replayed += n.replayHints(target)
+	}
+	return replayed
+}
+
+// replayHintsInBackground starts a replay unless one is still running, so a
+// slow target never holds up the maintenance loop.
+func (n *Node) replayHintsInBackground(alive map[string]bool) {
+	if !atomic.CompareAndSwapInt32(&n.replayingHints, 0, 1) {
+		return
+	}
+	go func() {
+		defer atomic.StoreInt32(&n.replayingHints, 0)
+		n.replayHintsTo(alive)
+	}()
+}
+
+func (n *Node) expireHints() {
+	expired, err := n.hints.Expire(HINT_TTL)
+	if err != nil {
+		log.Printf("[HINTS] Failed to persist hint queue: %v", err)
+	}
+	if expired > 0 {
+		atomic.AddInt64(&n.Metrics.HintsExpired, int64(expired))
+		log.Printf("[HINTS] Dropped %d expired hints", expired)
+	}
+}
@@ -0,0 +1,128 @@
+package node
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"tapestry/internal/id"
+)
+
+func TestHintQueuePersistence(t *testing.T) {
+	dir := t.TempDir()
+	q, err := NewHintQueue(dir)
+	if err != nil {
+		t.Fatalf("NewHintQueue failed: %v", err)
+	}
+
+	target := Neighbor{ID: id.NewRandomID(), Address: "localhost:9999"}
+	q.Add(target, Object{Key: "a", Data: []byte("1")})
+	q.Add(target, Object{Key: "a", Data: []byte("2")})
+	q.Add(target, Object{Key: "b", Data: []byte("x")})
+	if q.Len() != 2 {
+		t.Fatalf("Expected 2 hints after replacing one, got %d", q.Len())
+	}
+
+	reloaded, err := NewHintQueue(dir)
+	if err != nil {
+		t.Fatalf("Reload failed: %v", err)
+	}
+	targets := reloaded.Targets()
+	if len(targets) != 1 || !targets[0].ID.Equals(target.ID) || targets[0].Address != target.Address {
+		t.Fatalf("Reloaded targets %v do not match %v", targets, target)
+	}
+
+	hints, _ := reloaded.Take(target.ID.String())
+	if len(hints) != 2 || reloaded.Len() != 0 {
+		t.Fatalf("Take returned %d hints, %d left", len(hints), reloaded.Len())
+	}
+	for _, h := range hints {
+		if h.Object.Key == "a" && string(h.Object.Data) != "2" {
+			t.Errorf("Older hint for 'a' was kept: %q", h.Object.Data)
+		}
+	}
+
+	if again, _ := NewHintQueue(dir); again.Len() != 0 {
+		t.Errorf("Taken hints were not removed from disk")
+	}
+}
+
+func TestHintQueueExpiry(t *testing.T) {
+	q, _ := NewHintQueue("")
+	target := Neighbor{ID: id.NewRandomID(), Address: "localhost:9999"}
+	q.Add(target, Object{Key: "old"})
+	q.Requeue([]Hint{{TargetID: target.ID.String(), TargetAddr: target.Address, Object: Object{Key: "older"}, Created: time.Now().Add(-2 * time.Hour)}})
+
+	expired, _ := q.Expire(time.Hour)
+	if expired != 1 || q.Len() != 1 {
+		t.Errorf("Expected 1 expired and 1 remaining hint, got %d and %d", expired, q.Len())
+	}
+}
+
+func TestHintQueueAppendsWithoutPayloads(t *testing.T) {
+	dir := t.TempDir()
+	q, err := NewHintQueue(dir)
+	if err != nil {
+		t.Fatalf("NewHintQueue failed: %v", err)
+	}
+	defer q.Close()
+
+	size := func() int64 {
+		info, err := os.Stat(filepath.Join(dir, HINT_FILE_NAME))
+		if err != nil {
+			t.Fatalf("Stat failed: %v", err)
+		}
+		return info.Size()
+	}
+
+	target := Neighbor{ID: id.NewRandomID(), Address: "localhost:9999"}
+	payload := make([]byte, 64*1024)
+	q.Add(target, Object{Key: "big", Data: payload})
+	afterAdd := size()
+	q.Add(target, Object{Key: "small", Data: []byte("x")})
+	q.Take(target.ID.String())
+	if grown := size() - afterAdd; grown > int64(len(payload)) {
+		t.Errorf("Queueing and taking a small hint rewrote the big one: log grew by %d bytes", grown)
+	}
+
+	reloaded, err := NewHintQueue(dir)
+	if err != nil {
+		t.Fatalf("Reload failed: %v", err)
+	}
+	defer reloaded.Close()
+	if reloaded.Len() != 0 {
+		t.Errorf("Expected no hints after the take, got %d", reloaded.Len())
+	}
+}
+
+func TestHintQueueAppendAfterTornTail(t *testing.T) {
+	dir := t.TempDir()
+	q, err := NewHintQueue(dir)
+	if err != nil {
+		t.Fatalf("NewHintQueue failed: %v", err)
+	}
+	target := Neighbor{ID: id.NewRandomID(), Address: "localhost:9999"}
+	q.Add(target, Object{Key: "before"})
+	q.Close()
+
+	f, _ := os.OpenFile(filepath.Join(dir, HINT_FILE_NAME), os.O_APPEND|os.O_WRONLY, 0644)
+	f.WriteString(`{"op":"add","hint":{"targetId":"abc`)
+	f.Close()
+
+	q, err = NewHintQueue(dir)
+	if err != nil {
+		t.Fatalf("Reopen failed: %v", err)
+	}
+	q.Add(target, Object{Key: "after"})
+	q.Close()
+
+	q, err = NewHintQueue(dir)
+	if err != nil {
+		t.Fatalf("Second reopen failed: %v", err)
+	}
+	defer q.Close()
+	if q.Len() != 2 {
+		t.Errorf("Expected the hints on both sides of the torn tail, got %d", q.Len())
+	}
+}
@@ -1,20 +1,90 @@
 package node
 
 import (
+	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"log"
 	"net/http"
+	"strconv"
+	pb "tapestry/api/proto"
 	"tapestry/internal/id"
 	"time"
+	"unicode/utf8"
 )
 
+// Values are split into CHUNK_SIZE blocks before they are replicated, so the
+// upload limit only bounds how much one publish request may buffer.
+const DEFAULT_MAX_UPLOAD_SIZE = 256 << 20
+
 type Status struct {
-	ID           string     `json:"id"`
-	Port         int        `json:"port"`
-	RoutingTable [][]string `json:"routingTable"` 
-	Backpointers []string   `json:"backpointers"`
-	Objects      []Object   `json:"objects"`
+	ID           string       `json:"id"`
+	Port         int          `json:"port"`
+	RoutingTable [][]string   `json:"routingTable"` 
+	Backpointers []string     `json:"backpointers"`
+	Objects      []ObjectView `json:"objects"`
+}
+
+// ObjectView is the JSON form of an object. Data is plain text when the
+// payload is valid UTF-8 and base64 otherwise.
+type ObjectView struct {
+	Key      string
+	Data     string
+	Encoding string
+	Size     int64
+	Chunks   int          `json:",omitempty"`
+	Version  VectorClock
+	Siblings []ObjectView `json:",omitempty"`
+	Deleted  bool         `json:",omitempty"`
+	Expires  *time.Time   `json:",omitempty"`
+}
+
+func viewObject(obj Object) ObjectView {
+	v := ObjectView{Key: obj.Key, Size: obj.Size, Chunks: len(obj.Chunks), Encoding: "utf8", Version: obj.Version, Deleted: obj.Deleted}
+	if utf8.Valid(obj.Data) {
+		v.Data = string(obj.Data)
+	} else {
+		v.Data = base64.StdEncoding.EncodeToString(obj.Data)
+		v.Encoding = "base64"
+	}
+	if !obj.ExpiresAt.IsZero() {
+		v.Expires = &obj.ExpiresAt
+	}
+	for _, sib := range obj.Siblings {
+		v.Siblings = append(v.Siblings, viewObject(sib))
+	}
+	return v
+}
+
+// parseTTL accepts a Go duration ("90s", "1h") or a plain number of seconds.
+func parseTTL(s string) (time.Duration, error) {
+	if s == "" {
+		return 0, nil
+	}
+	if secs, err := strconv.Atoi(s); err == nil {
+		return time.Duration(secs) * time.Second, nil
+	}
+	ttl, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid ttl %q", s)
+	}
+	return ttl, nil
+}
+
+type TraceHop struct {
+	ID        string  `json:"id"`
+	Address   string  `json:"address"`
+	Level     int     `json:"level"`
+	Surrogate bool    `json:"surrogate"`
+	RTT       float64 `json:"rttMs"`
+}
+
+type TraceResult struct {
+	Target      string     `json:"target"`
+	Hops        []TraceHop `json:"hops"`
+	ReachedRoot bool       `json:"reachedRoot"`
 }
 
 func allowCORS(h http.HandlerFunc) http.HandlerFunc {
@@ -37,6 +107,8 @@ func (n *Node) StartHttpServer(port int) {
 	http.HandleFunc("/publish", allowCORS(n.publishHandler))
 	http.HandleFunc("/find", allowCORS(n.findHandler))
 	http.HandleFunc("/unpublish", allowCORS(n.unpublishHandler))
+	http.HandleFunc("/trace", allowCORS(n.traceHandler))
+	http.HandleFunc("/metrics", allowCORS(n.metricsHandler))
 	http.HandleFunc("/leave", allowCORS(n.leaveHandler)) 
 
 	if err := http.ListenAndServe(fmt.Sprintf(":%d", port), nil); err != nil {
@@ -68,12 +140,10 @@ func (n *Node) statusHandler(w http.ResponseWriter, r *http.Request) {
 	}
 	n.bpLock.RUnlock()
 
-	n.objLock.RLock()
-	objDisplay := []Object{}
-	for _, o := range n.LocalObjects {
-		objDisplay = append(objDisplay, o)
+	objDisplay := []ObjectView{}
+	for _, obj := range n.Objects.List() {
+		objDisplay = append(objDisplay, viewObject(obj))
 	}
-	n.objLock.RUnlock()
 
 	status := Status{
 		ID:           n.ID.String(),
@@ -86,13 +156,69 @@ func (n *Node) statusHandler(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(status)
 }
 
+// bodyErrorStatus reports an oversized request body as such rather than as a
+// malformed one.
+func bodyErrorStatus(err error) int {
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		return http.StatusRequestEntityTooLarge
+	}
+	return http.StatusBadRequest
+}
+
+// publishHandler accepts either a JSON body {"key", "value", "encoding"}
+// where encoding may be "base64", or a raw application/octet-stream body
+// with the key in the query string. An optional ?w= sets the write quorum;
+// a "ttl" field (or ?ttl= for raw bodies) makes the object expire.
+// Bodies larger than the node's MaxUploadSize are refused.
 func (n *Node) publishHandler(w http.ResponseWriter, r *http.Request) {
-	var data map[string]string
-	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+	var key, ttlParam string
+	var value []byte
+
+	limit := n.MaxUploadSize
+	if limit <= 0 {
+		limit = DEFAULT_MAX_UPLOAD_SIZE
+	}
+	r.Body = http.MaxBytesReader(w, r.Body, limit)
+	if r.Header.Get("Content-Type") == "application/octet-stream" {
+		key = r.URL.Query().Get("key")
+		ttlParam = r.URL.Query().Get("ttl")
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, err.Error(), bodyErrorStatus(err))
+			return
+		}
+		value = body
+	} else {
+		var data map[string]string
+		if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+			http.Error(w, err.Error(), bodyErrorStatus(err))
+			return
+		}
+		key = data["key"]
+		ttlParam = data["ttl"]
+		value = []byte(data["value"])
+		if data["encoding"] == "base64" {
+			decoded, err := base64.StdEncoding.DecodeString(data["value"])
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			value = decoded
+		}
+	}
+
+	if key == "" {
+		http.Error(w, "missing key", http.StatusBadRequest)
+		return
+	}
+	ttl, err := parseTTL(ttlParam)
+	if err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
-	err := n.StoreAndPublish(data["key"], data["value"])
+	quorum, _ := strconv.Atoi(r.URL.Query().Get("w"))
+	err = n.StoreAndPublishWithOptions(r.Context(), key, value, WriteOptions{W: quorum, TTL: ttl})
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -106,22 +232,77 @@ func (n *Node) findHandler(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
-	obj, err := n.Get(data["key"])
+	quorum, _ := strconv.Atoi(r.URL.Query().Get("r"))
+	repair := r.URL.Query().Get("repair") == "1"
+	obj, err := n.GetWithOptions(r.Context(), data["key"], ReadOptions{R: quorum, ReadRepair: repair})
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusNotFound)
 		return
 	}
+	if data["format"] == "raw" {
+		w.Header().Set("Content-Type", "application/octet-stream")
+		w.Write(obj.Data)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(viewObject(obj))
+}
+
+func (n *Node) traceHandler(w http.ResponseWriter, r *http.Request) {
+	var data map[string]string
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var target id.ID
+	if raw, ok := data["id"]; ok {
+		parsed, err := id.Parse(raw)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		target = parsed
+	} else {
+		target = id.Hash(data["key"])
+	}
+
+	resp, err := n.TraceRoute(r.Context(), &pb.TraceRequest{
+		TargetId: &pb.NodeID{Bytes: target.Bytes()},
+		HopLimit: MAX_HOPS,
+	})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	result := TraceResult{Target: target.String(), ReachedRoot: resp.ReachedRoot}
+	for _, h := range resp.Hops {
+		nb, _ := NeighborFromProto(h.Node)
+		result.Hops = append(result.Hops, TraceHop{
+			ID:        nb.ID.String(),
+			Address:   nb.Address,
+			Level:     int(h.Level),
+			Surrogate: h.Surrogate,
+			RTT:       float64(h.RttMicros) / 1000.0,
+		})
+	}
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(obj)
+	json.NewEncoder(w).Encode(result)
 }
 
 func (n *Node) unpublishHandler(w http.ResponseWriter, r *http.Request) {
 	var data map[string]string
 	json.NewDecoder(r.Body).Decode(&data)
-	n.Remove(data["key"])
+	n.remove(r.Context(), data["key"])
 	w.WriteHeader(http.StatusOK)
 }
 
+func (n *Node) metricsHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(n.MetricsSnapshot())
+}
+
 func (n *Node) leaveHandler(w http.ResponseWriter, r *http.Request) {
     go func() {
         time.Sleep(100 * time.Millisecond)
@@ -0,0 +1,122 @@
+package node
+
+import (
+	"crypto/ed25519"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"tapestry/internal/id"
+)
+
+const (
+	ID_FILE_NAME    = "node.id"
+	KEY_FILE_NAME   = "node.key"
+	TABLE_FILE_NAME = "routing_table.json"
+)
+
+type savedNeighbor struct {
+	ID      string `json:"id"`
+	Address string `json:"address"`
+}
+
+// LoadOrCreateKey returns the node key persisted in dir, generating and
+// saving a fresh one on first start. The node ID is derived from the key, so
+// the node keeps its position across restarts. A dir that still holds a
+// legacy node.id and no key is refused rather than given a new ID.
+func LoadOrCreateKey(dir string) (ed25519.PrivateKey, error) {
+	path := filepath.Join(dir, KEY_FILE_NAME)
+
+	raw, err := os.ReadFile(path)
+	if err == nil {
+		block, _ := pem.Decode(raw)
+		if block == nil {
+			return nil, fmt.Errorf("corrupt node key in %s", path)
+		}
+		parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("corrupt node key in %s: %w", path, err)
+		}
+		key, ok := parsed.(ed25519.PrivateKey)
+		if !ok {
+			return nil, fmt.Errorf("node key in %s is not Ed25519", path)
+		}
+		return key, nil
+	}
+	if !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	// The old ID cannot be kept, since IDs now derive from the key, and
+	// quietly taking a new one would move the node in the mesh.
+	legacy := filepath.Join(dir, ID_FILE_NAME)
+	if _, err := os.Stat(legacy); err == nil {
+		return nil, fmt.Errorf("%s holds a node ID from before IDs were derived from keys; remove it to start with a new key-derived ID", legacy)
+	}
+
+	_, key, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		return nil, err
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create data directory %s: %w", dir, err)
+	}
+	if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), 0600); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+func (n *Node) SaveRoutingTable(dir string) error {
+	var saved []savedNeighbor
+	n.Table.lock.RLock()
+	for i := 0; i < id.DIGITS; i++ {
+		for j := 0; j < id.RADIX; j++ {
+			for _, nb := range n.Table.rows[i][j] {
+				saved = append(saved, savedNeighbor{ID: nb.ID.String(), Address: nb.Address})
+			}
+		}
+	}
+	n.Table.lock.RUnlock()
+
+	data, err := json.MarshalIndent(saved, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, TABLE_FILE_NAME), data, 0644)
+}
+
+func LoadRoutingTable(dir string) ([]Neighbor, error) {
+	data, err := os.ReadFile(filepath.Join(dir, TABLE_FILE_NAME))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var saved []savedNeighbor
+	if err := json.Unmarshal(data, &saved); err != nil {
+		return nil, fmt.Errorf("corrupt routing table snapshot: %w", err)
+	}
+
+	var neighbors []Neighbor
+	for _, s := range saved {
+		nbID, err := id.Parse(s.ID)
+		if err != nil {
+			continue
+		}
+		neighbors = append(neighbors, Neighbor{ID: nbID, Address: s.Address})
+	}
+	return neighbors, nil
+}
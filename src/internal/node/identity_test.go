@@ -0,0 +1,62 @@
+package node
+
+import (
+	"os"
+	"path/filepath"
+	"tapestry/internal/id"
+	"testing"
+)
+
+func TestLoadOrCreateKey(t *testing.T) {
+	dir := t.TempDir()
+
+	first, err := LoadOrCreateKey(dir)
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	second, err := LoadOrCreateKey(dir)
+	if err != nil {
+		t.Fatalf("Reload failed: %v", err)
+	}
+	if !first.Equal(second) {
+		t.Errorf("Key changed across restarts")
+	}
+
+	os.WriteFile(filepath.Join(dir, KEY_FILE_NAME), []byte("garbage"), 0600)
+	if _, err := LoadOrCreateKey(dir); err == nil {
+		t.Errorf("Expected an error for a corrupt key file")
+	}
+
+	legacy := t.TempDir()
+	os.WriteFile(filepath.Join(legacy, ID_FILE_NAME), []byte(id.NewRandomID().String()), 0644)
+	if _, err := LoadOrCreateKey(legacy); err == nil {
+		t.Errorf("Expected an error for a legacy node ID without a key")
+	}
+	if _, err := os.Stat(filepath.Join(legacy, KEY_FILE_NAME)); err == nil {
+		t.Errorf("A key was created next to the legacy node ID")
+	}
+}
+
+func TestRoutingTableSnapshot(t *testing.T) {
+	dir := t.TempDir()
+	localID := id.ZeroID
+	n := &Node{ID: localID, Table: NewRoutingTable(localID)}
+
+	nbID := id.ZeroID.SetDigit(0, 9)
+	n.Table.Add(Neighbor{ID: nbID, Address: "localhost:9999"})
+
+	if err := n.SaveRoutingTable(dir); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	neighbors, err := LoadRoutingTable(dir)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if len(neighbors) != 1 || !neighbors[0].ID.Equals(nbID) || neighbors[0].Address != "localhost:9999" {
+		t.Errorf("Unexpected snapshot contents: %+v", neighbors)
+	}
+
+	if neighbors, err := LoadRoutingTable(t.TempDir()); err != nil || neighbors != nil {
+		t.Errorf("Missing snapshot should load as empty, got %v (%v)", neighbors, err)
+	}
+}
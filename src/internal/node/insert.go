@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"log"
 	"math/rand"
+	"sort"
 	"time"
 	"sync"
 
@@ -12,6 +13,11 @@ import (
 	"tapestry/internal/id"
 )
 
+const (
+	MULTICAST_ATTEMPTS    = 3
+	MULTICAST_RETRY_DELAY = 500 * time.Millisecond
+)
+
 func (n *Node) Join(bootstrapAddrs []string) error {
 	rand.Shuffle(len(bootstrapAddrs), func(i, j int) {
 		bootstrapAddrs[i], bootstrapAddrs[j] = bootstrapAddrs[j], bootstrapAddrs[i]
@@ -25,9 +31,12 @@ func (n *Node) Join(bootstrapAddrs []string) error {
 		if addr == n.Address { continue }
 
 		log.Printf("Attempting to join via %s...", addr)
-		bsClient, err = GetClient(addr)
+		// The bootstrap's ID is not known yet, so only its chain is checked.
+		bsClient, err = n.getClient(Neighbor{Address: addr})
 		if err == nil {
-			_, pingErr := bsClient.Ping(context.Background(), &pb.Nothing{})
+			ctx, cancel := n.rpcContext(context.Background())
+			_, pingErr := bsClient.Ping(ctx, &pb.Nothing{})
+			cancel()
 			if pingErr == nil {
 				connectedAddr = addr
 				break
@@ -43,21 +52,21 @@ func (n *Node) Join(bootstrapAddrs []string) error {
 
 	log.Printf("Successfully bonded with Gateway: %s", connectedAddr)
 
-	req := &pb.RouteRequest{
+	ctx, cancel := n.routeContext(context.Background())
+	traceResp, err := bsClient.TraceRoute(ctx, &pb.TraceRequest{
 		TargetId: &pb.NodeID{Bytes: n.ID.Bytes()},
-		SourceId: &pb.NodeID{Bytes: n.ID.Bytes()},
-	}
-	
-	resp, err := bsClient.GetNextHop(context.Background(), req)
+		HopLimit: MAX_HOPS,
+	})
+	cancel()
 	if err != nil {
 		return fmt.Errorf("bootstrap route failed: %v", err)
 	}
-
-	var surrogateNeighbor Neighbor
-	if resp.NextHop != nil {
-		surrogateNeighbor, _ = NeighborFromProto(resp.NextHop)
+	if len(traceResp.Hops) == 0 {
+		return fmt.Errorf("bootstrap route returned no hops")
 	}
 
+	surrogateNeighbor, _ := NeighborFromProto(traceResp.Hops[len(traceResp.Hops)-1].Node)
+
 	added := false
 	for i := 0; i < 3; i++ {
 		if n.AddNeighborSafe(surrogateNeighbor) {
@@ -72,45 +81,147 @@ func (n *Node) Join(bootstrapAddrs []string) error {
 		log.Printf("[WARNING] Failed to bond with Surrogate %s. Node might be isolated!", surrogateNeighbor.Address)
 	}
 
-	copyClient, err := GetClient(surrogateNeighbor.Address)
-	if err == nil {
-		defer copyClient.Close()
-		rtResp, err := copyClient.GetRoutingTable(context.Background(), &pb.Nothing{})
-		if err == nil {
-			n.populateTable(rtResp)
-		}
+	reached, err := n.acknowledgedMulticast(surrogateNeighbor)
+	if err != nil {
+		return fmt.Errorf("join multicast failed: %v", err)
 	}
+	log.Printf("Join multicast acknowledged by %d nodes.", len(reached))
+
+	n.buildTable(append(reached, surrogateNeighbor), id.SharedPrefixLength(n.ID, surrogateNeighbor.ID))
 
 	n.notifyNeighbors()
 
+	if count := n.Objects.Len(); count > 0 {
+		log.Printf("Republishing %d stored objects after join.", count)
+		go n.republishObjects()
+	}
+
 	return nil
 }
 
-func (n *Node) populateTable(resp *pb.RTCopyResponse) {
+// acknowledgedMulticast asks the surrogate to announce us to every node that
+// shares our prefix with it. It blocks until the whole multicast tree acked,
+// retrying a multicast that some branch failed; the retry goes around the
+// nodes that could not be reached, which are suspect by then.
+func (n *Node) acknowledgedMulticast(surrogate Neighbor) ([]Neighbor, error) {
+	client, err := n.getClient(surrogate)
+	if err != nil {
+		return nil, err
+	}
+	defer client.Close()
+
+	var resp *pb.MulticastResponse
+	for attempt := 0; attempt < MULTICAST_ATTEMPTS; attempt++ {
+		if attempt > 0 {
+			log.Printf("Join multicast incomplete: %v. Retrying.", err)
+			time.Sleep(MULTICAST_RETRY_DELAY)
+		}
+		ctx, cancel := n.routeContext(context.Background())
+		resp, err = client.NotifyMulticast(ctx, &pb.MulticastRequest{
+			NewNode: n.toProtoNeighbor(),
+			Level:   int32(id.SharedPrefixLength(n.ID, surrogate.ID)),
+		})
+		cancel()
+		if err == nil {
+			break
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var reached []Neighbor
+	for _, nbProto := range resp.Reached {
+		nb, err := NeighborFromProto(nbProto)
+		if err != nil || nb.ID.Equals(n.ID) {
+			continue
+		}
+		reached = append(reached, nb)
+	}
+	return reached, nil
+}
+
+// buildTable runs the nearest-neighbor construction: starting from the nodes
+// sharing our deepest prefix, keep the K_NEAREST closest at each level and ask
+// them for their neighbors one level up until row 0 is filled.
+func (n *Node) buildTable(seed []Neighbor, level int) {
+	list := seed
+	for ; level >= 0; level-- {
+		list = closestNeighbors(n.probeAll(list), K_NEAREST)
+		log.Printf("Bootstrap: Level %d kept %d nearest nodes.", level, len(list))
+		if level == 0 {
+			break
+		}
+
+		candidates := list
+		for _, nb := range list {
+			client, err := n.getClient(nb)
+			if err != nil {
+				continue
+			}
+			ctx, cancel := n.rpcContext(context.Background())
+			resp, err := client.GetLevelNeighbors(ctx, &pb.LevelRequest{Level: int32(level - 1)})
+			cancel()
+			client.Close()
+			if err != nil {
+				continue
+			}
+			for _, nbProto := range resp.Neighbors {
+				c, err := NeighborFromProto(nbProto)
+				if err == nil && id.SharedPrefixLength(n.ID, c.ID) >= level-1 {
+					candidates = append(candidates, c)
+				}
+			}
+		}
+		list = candidates
+	}
+}
+
+// probeAll measures the RTT to every distinct candidate, offers each reachable
+// one to the routing table and returns them with their latency filled in.
+func (n *Node) probeAll(candidates []Neighbor) []Neighbor {
 	sem := make(chan struct{}, 5) 
 	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var probed []Neighbor
 
-	count := 0
-	for _, entry := range resp.Entries {
-		for _, nbProto := range entry.Neighbors {
-			nb, _ := NeighborFromProto(nbProto)
-			if nb.ID.Equals(n.ID) { continue }
-
-			wg.Add(1)
-			go func(neighbor Neighbor) {
-				defer wg.Done()
-				sem <- struct{}{} 
-				n.AddNeighborSafe(neighbor)
-				<-sem
-			}(nb)
-			count++
+	seen := make(map[string]bool)
+	for _, nb := range candidates {
+		if nb.ID.Equals(n.ID) || seen[nb.ID.String()] {
+			continue
 		}
+		seen[nb.ID.String()] = true
+
+		wg.Add(1)
+		go func(neighbor Neighbor) {
+			defer wg.Done()
+			sem <- struct{}{} 
+			defer func() { <-sem }()
+
+			rtt, err := n.probe(context.Background(), neighbor)
+			if err != nil {
+				return
+			}
+			neighbor.Latency = rtt
+			n.Table.Add(neighbor)
+
+			mu.Lock()
+			probed = append(probed, neighbor)
+			mu.Unlock()
+		}(nb)
 	}
-	
-	go func() {
-		wg.Wait()
-		log.Printf("Bootstrap: Processed %d candidates from surrogate table.", count)
-	}()
+	wg.Wait()
+	return probed
+}
+
+func closestNeighbors(neighbors []Neighbor, k int) []Neighbor {
+	sort.Slice(neighbors, func(i, j int) bool {
+		return neighbors[i].Latency < neighbors[j].Latency
+	})
+	if len(neighbors) > k {
+		neighbors = neighbors[:k]
+	}
+	return neighbors
 }
 
 func (n *Node) notifyNeighbors() {
@@ -128,16 +239,18 @@ func (n *Node) notifyNeighbors() {
 
 	for _, nb := range neighbors {
 		go func(target Neighbor) {
-			client, err := GetClient(target.Address)
+			client, err := n.getClient(target)
 			if err == nil {
 				defer client.Close()
 				level := id.SharedPrefixLength(target.ID, n.ID)
 				
 				req := &pb.BackpointerRequest{
-					From:  &pb.Neighbor{Id: &pb.NodeID{Bytes: n.ID.Bytes()}, Address: n.Address},
+					From:  n.toProtoNeighbor(),
 					Level: int32(level),
 				}
-				client.AddBackpointer(context.Background(), req)
+				ctx, cancel := n.rpcContext(context.Background())
+				defer cancel()
+				client.AddBackpointer(ctx, req)
 			}
 		}(nb)
 	}
@@ -0,0 +1,30 @@
+package node
+
+import (
+	"tapestry/internal/id"
+	"testing"
+	"time"
+)
+
+func TestClosestNeighbors(t *testing.T) {
+	var neighbors []Neighbor
+	for i := 0; i < 12; i++ {
+		neighbors = append(neighbors, Neighbor{
+			ID:      id.NewRandomID(),
+			Latency: time.Duration(12-i) * time.Millisecond,
+		})
+	}
+
+	closest := closestNeighbors(neighbors, K_NEAREST)
+	if len(closest) != K_NEAREST {
+		t.Fatalf("Expected %d neighbors, got %d", K_NEAREST, len(closest))
+	}
+	if closest[0].Latency != time.Millisecond {
+		t.Errorf("Expected nearest latency 1ms, got %v", closest[0].Latency)
+	}
+	for i := 1; i < len(closest); i++ {
+		if closest[i].Latency < closest[i-1].Latency {
+			t.Errorf("Neighbors not sorted by latency at %d", i)
+		}
+	}
+}
@@ -2,11 +2,14 @@ package node
 
 import (
 	"context"
+	"crypto/ed25519"
 	"log"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	pb "tapestry/api/proto"
+	"tapestry/internal/id"
 )
 
 func (n *Node) Leave() error {
@@ -26,10 +29,12 @@ func (n *Node) Leave() error {
 		wg.Add(1)
 		go func(target Neighbor) {
 			defer wg.Done()
-			client, err := GetClient(target.Address)
+			client, err := n.getClient(target)
 			if err == nil {
 				defer client.Close()
-				client.NotifyLeave(context.Background(), n.toProtoNeighbor())
+				ctx, cancel := n.rpcContext(context.Background())
+				defer cancel()
+				client.NotifyLeave(ctx, n.toProtoNeighbor())
 			}
 		}(bp)
 	}
@@ -45,6 +50,8 @@ func (n *Node) Leave() error {
 		log.Println("[LEAVE] Backpointer notification timed out")
 	}
 
+	n.handoffPointers()
+
 	n.Stop()
 	
 	close(n.ExitChan)
@@ -53,14 +60,12 @@ func (n *Node) Leave() error {
 }
 
 func (n *Node) redistributeData() {
-	n.objLock.RLock()
-	defer n.objLock.RUnlock()
-
-	if len(n.LocalObjects) == 0 {
+	objects := n.Objects.List()
+	if len(objects) == 0 {
 		return
 	}
 
-	log.Printf("[LEAVE] Redistributing %d objects to neighbors...", len(n.LocalObjects))
+	log.Printf("[LEAVE] Redistributing %d objects to neighbors...", len(objects))
 
 	candidates := n.SelectRandomNeighbors(3)
 	if len(candidates) == 0 {
@@ -70,30 +75,30 @@ func (n *Node) redistributeData() {
 
 	var wg sync.WaitGroup
 	i := 0
-	for _, obj := range n.LocalObjects {
+	for _, obj := range objects {
 		target := candidates[i%len(candidates)]
 		i++
 
 		wg.Add(1)
-		go func(t Neighbor, k, d string) {
+		go func(t Neighbor, o Object) {
 			defer wg.Done()
-			client, err := GetClient(t.Address)
+			client, err := n.getClient(t)
 			if err != nil {
 				log.Printf("[LEAVE] Failed to handoff object to %s", t.Address)
 				return
 			}
 			defer client.Close()
 
-			_, err = client.Replicate(context.Background(), &pb.ReplicateRequest{
-				Key:  k,
-				Data: d,
-			})
+			o.Owner = t.ID.String()
+			ctx, cancel := n.rpcContext(context.Background())
+			defer cancel()
+			_, err = client.Replicate(ctx, o.toReplicateRequest())
 			if err == nil {
-				log.Printf("[LEAVE] Handed off '%s' to %s", k, t.Address)
+				log.Printf("[LEAVE] Handed off '%s' to %s", o.Key, t.Address)
 			} else {
 				log.Printf("[LEAVE] Error replicating to %s: %v", t.Address, err)
 			}
-		}(target, obj.Key, obj.Data)
+		}(target, obj)
 	}
 	
 	done := make(chan struct{})
@@ -110,13 +115,66 @@ func (n *Node) redistributeData() {
 	}
 }
 
+// handoffPointers sends every location pointer we hold towards the node that
+// becomes root for its object once we are gone.
+func (n *Node) handoffPointers() int {
+	n.lpLock.RLock()
+	var objIDs []id.ID
+	for objID := range n.LocationPointers {
+		objIDs = append(objIDs, objID)
+	}
+	n.lpLock.RUnlock()
+
+	sets := make(map[id.ID]*pb.PointerSet)
+	for _, objID := range objIDs {
+		if set := n.pointerSet(objID); len(set.Publishers) > 0 {
+			sets[objID] = set
+		}
+	}
+
+	if len(sets) == 0 {
+		return 0
+	}
+
+	batches := make(map[string][]*pb.PointerSet)
+	targets := make(map[string]Neighbor)
+	for objID, set := range sets {
+		nextHop, isRoot := n.computeNextHopExcludingSelf(objID)
+		if isRoot {
+			continue
+		}
+		key := nextHop.ID.String()
+		targets[key] = nextHop
+		batches[key] = append(batches[key], set)
+	}
+
+	handedOff := 0
+	for key, batch := range batches {
+		if err := n.sendPointers(context.Background(), targets[key], batch, MAX_HOPS); err != nil {
+			log.Printf("[LEAVE] Failed to hand off %d pointer sets to %s: %v", len(batch), targets[key].Address, err)
+			continue
+		}
+		for _, set := range batch {
+			handedOff += len(set.Publishers)
+		}
+	}
+
+	atomic.AddInt64(&n.pointersHandedOff, int64(handedOff))
+	log.Printf("[LEAVE] Handed off %d location pointers for %d objects to %d nodes.", handedOff, len(sets), len(batches))
+	return handedOff
+}
+
 func (n *Node) NotifyLeave(ctx context.Context, req *pb.Neighbor) (*pb.Nothing, error) {
 	leavingNode, err := NeighborFromProto(req)
 	if err != nil { return nil, err }
+	if err := verifyPeerIdentity(ctx, leavingNode.ID); err != nil { return nil, err }
 
 	log.Printf("[LEAVE] Notification: Node %s is leaving. Removing from table.", leavingNode.ID)
 	
 	n.Table.Remove(leavingNode.ID)
+	if n.pool != nil {
+		n.pool.Remove(leavingNode.Address)
+	}
 	
 	n.bpLock.Lock()
 	delete(n.Backpointers, leavingNode.ID.String())
@@ -126,8 +184,13 @@ func (n *Node) NotifyLeave(ctx context.Context, req *pb.Neighbor) (*pb.Nothing,
 }
 
 func (n *Node) toProtoNeighbor() *pb.Neighbor {
-	return &pb.Neighbor{
+	nb := &pb.Neighbor{
 		Id:      &pb.NodeID{Bytes: n.ID.Bytes()},
 		Address: n.Address,
 	}
+	if n.key != nil {
+		nb.PublicKey = n.key.Public().(ed25519.PublicKey)
+		nb.Signature = n.sign(neighborMessage(n.ID, n.Address))
+	}
+	return nb
 }
\ No newline at end of file
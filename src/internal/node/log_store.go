@@ -0,0 +1,159 @@
+package node
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"tapestry/internal/id"
+)
+
+const (
+	LOG_FILE_NAME       = "objects.log"
+	COMPACT_MIN_RECORDS = 1000
+)
+
+type logRecord struct {
+	Op     string  `json:"op"`
+	ID     string  `json:"id"`
+	Object *Object `json:"object,omitempty"`
+}
+
+// LogStore is a durable Store backed by an append-only log of JSON records.
+// The full object set is kept in memory and the log is rewritten once stale
+// records outnumber live ones.
+type LogStore struct {
+	log     *appendLog
+	objects map[id.ID]Object
+	stale   int
+	lock    sync.RWMutex
+}
+
+func OpenLogStore(dir string) (*LogStore, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create store directory %s: %w", dir, err)
+	}
+
+	s := &LogStore{objects: make(map[id.ID]Object)}
+	l, err := openAppendLog(filepath.Join(dir, LOG_FILE_NAME), "[STORE]", s.apply)
+	if err != nil {
+		return nil, err
+	}
+	s.log = l
+
+	log.Printf("[STORE] Loaded %d objects from %s", len(s.objects), l.path)
+	return s, nil
+}
+
+func (s *LogStore) apply(raw json.RawMessage) {
+	var rec logRecord
+	if err := json.Unmarshal(raw, &rec); err != nil {
+		return
+	}
+	objID, err := id.Parse(rec.ID)
+	if err != nil {
+		return
+	}
+	if _, exists := s.objects[objID]; exists {
+		s.stale++
+	}
+
+	switch rec.Op {
+	case "put":
+		if rec.Object != nil {
+			s.objects[objID] = *rec.Object
+		}
+	case "del":
+		delete(s.objects, objID)
+		s.stale++
+	}
+}
+
+func (s *LogStore) Get(objID id.ID) (Object, bool) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	obj, ok := s.objects[objID]
+	return obj, ok
+}
+
+func (s *LogStore) Put(objID id.ID, obj Object) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if err := s.log.append(logRecord{Op: "put", ID: objID.String(), Object: &obj}); err != nil {
+		return fmt.Errorf("failed to persist '%s': %w", obj.Key, err)
+	}
+	if _, exists := s.objects[objID]; exists {
+		s.stale++
+	}
+	s.objects[objID] = obj
+	return s.maybeCompact()
+}
+
+func (s *LogStore) Delete(objID id.ID) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if _, exists := s.objects[objID]; !exists {
+		return nil
+	}
+	if err := s.log.append(logRecord{Op: "del", ID: objID.String()}); err != nil {
+		return fmt.Errorf("failed to persist delete of %s: %w", objID, err)
+	}
+	delete(s.objects, objID)
+	s.stale += 2
+	return s.maybeCompact()
+}
+
+func (s *LogStore) List() []Object {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	result := make([]Object, 0, len(s.objects))
+	for _, obj := range s.objects {
+		result = append(result, obj)
+	}
+	return result
+}
+
+func (s *LogStore) Len() int {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return len(s.objects)
+}
+
+func (s *LogStore) Close() error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.log.close()
+}
+
+func (s *LogStore) maybeCompact() error {
+	if !needsCompaction(s.stale, len(s.objects)) {
+		return nil
+	}
+	return s.compact()
+}
+
+// compact rewrites the log with one record per live object. Callers must
+// hold the write lock.
+func (s *LogStore) compact() error {
+	err := s.log.rewrite(func(write func(rec any) error) error {
+		for objID, obj := range s.objects {
+			obj := obj
+			if err := write(logRecord{Op: "put", ID: objID.String(), Object: &obj}); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	log.Printf("[STORE] Compacted %s: dropped %d stale records", s.log.path, s.stale)
+	s.stale = 0
+	return nil
+}
@@ -2,9 +2,15 @@ package node
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"log"
+	"sync"
+
 	pb "tapestry/api/proto"
 	"tapestry/internal/id"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 func (n *Node) GetRoutingTable(ctx context.Context, req *pb.Nothing) (*pb.RTCopyResponse, error) {
@@ -30,11 +36,36 @@ func (n *Node) GetRoutingTable(ctx context.Context, req *pb.Nothing) (*pb.RTCopy
 	}, nil
 }
 
+func (n *Node) GetLevelNeighbors(ctx context.Context, req *pb.LevelRequest) (*pb.NeighborList, error) {
+	level := int(req.Level)
+	seen := make(map[string]bool)
+	var neighbors []*pb.Neighbor
+
+	for _, nb := range n.Table.GetLevel(level) {
+		seen[nb.ID.String()] = true
+		neighbors = append(neighbors, nb.ToProto())
+	}
+
+	n.bpLock.RLock()
+	for key, bp := range n.Backpointers {
+		if !seen[key] && id.SharedPrefixLength(n.ID, bp.ID) == level {
+			seen[key] = true
+			neighbors = append(neighbors, bp.ToProto())
+		}
+	}
+	n.bpLock.RUnlock()
+
+	return &pb.NeighborList{Neighbors: neighbors}, nil
+}
+
 func (n *Node) AddBackpointer(ctx context.Context, req *pb.BackpointerRequest) (*pb.Nothing, error) {
 	neighbor, err := NeighborFromProto(req.From)
 	if err != nil {
 		return nil, err
 	}
+	if err := verifyPeerIdentity(ctx, neighbor.ID); err != nil {
+		return nil, err
+	}
 
 	n.bpLock.Lock()
 	n.Backpointers[neighbor.ID.String()] = neighbor
@@ -57,6 +88,9 @@ func (n *Node) RemoveBackpointer(ctx context.Context, req *pb.Neighbor) (*pb.Not
 	if err != nil {
 		return nil, err
 	}
+	if err := verifyPeerIdentity(ctx, neighbor.ID); err != nil {
+		return nil, err
+	}
 
 	n.bpLock.Lock()
 	delete(n.Backpointers, neighbor.ID.String())
@@ -65,15 +99,111 @@ func (n *Node) RemoveBackpointer(ctx context.Context, req *pb.Neighbor) (*pb.Not
 	return &pb.Nothing{}, nil
 }
 
-func (n *Node) NotifyMulticast(ctx context.Context, req *pb.MulticastRequest) (*pb.Nothing, error) {
+func (n *Node) NotifyMulticast(ctx context.Context, req *pb.MulticastRequest) (*pb.MulticastResponse, error) {
 	newNode, err := NeighborFromProto(req.NewNode)
 	if err != nil {
 		return nil, err
 	}
 
-	log.Printf("Node %s received Multicast notification for new node %s", n.ID, newNode.ID)
-	
-	go n.AddNeighborSafe(newNode)
-	
-	return &pb.Nothing{}, nil
-}
\ No newline at end of file
+	log.Printf("Node %s received Multicast notification for new node %s (Level %d)", n.ID, newNode.ID, req.Level)
+
+	reached, err := n.multicast(ctx, newNode, int(req.Level))
+	if err != nil {
+		// Aborted, so the sender does not blame its own link to us.
+		return nil, status.Error(codes.Aborted, err.Error())
+	}
+	return &pb.MulticastResponse{Reached: reached}, nil
+}
+
+// multicast forwards the notification to one node per non-empty slot at
+// every level from `level` down, so each node sharing our first `level`
+// digits sees it exactly once. It returns only after every branch acked, or
+// with an error naming the branches where no candidate did. Candidates that
+// could not be reached are marked suspect and skipped when the join retries.
+func (n *Node) multicast(ctx context.Context, newNode Neighbor, level int) ([]*pb.Neighbor, error) {
+	type branch struct {
+		candidates []Neighbor
+		level      int
+	}
+	var branches []branch
+
+	n.Table.lock.RLock()
+	for l := level; l < id.DIGITS; l++ {
+		ownDigit := n.ID.GetDigit(l)
+		for d := 0; d < id.RADIX; d++ {
+			if d == ownDigit {
+				continue
+			}
+			var candidates []Neighbor
+			for _, nb := range n.Table.rows[l][d] {
+				if !nb.ID.Equals(newNode.ID) && !n.Table.suspects[nb.ID] {
+					candidates = append(candidates, nb)
+				}
+			}
+			if len(candidates) > 0 {
+				branches = append(branches, branch{candidates: candidates, level: l + 1})
+			}
+		}
+	}
+	n.Table.lock.RUnlock()
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var reached []*pb.Neighbor
+	var errs []error
+
+	for _, b := range branches {
+		wg.Add(1)
+		go func(b branch) {
+			defer wg.Done()
+			req := &pb.MulticastRequest{NewNode: newNode.ToProto(), Level: int32(b.level)}
+			var lastErr error
+			for _, target := range b.candidates {
+				client, err := n.getClient(target)
+				if err != nil {
+					n.Table.MarkSuspect(target.ID)
+					lastErr = err
+					continue
+				}
+				resp, err := client.NotifyMulticast(ctx, req)
+				client.Close()
+				if err != nil {
+					log.Printf("[MULTICAST] %s did not ack: %v", target.Address, err)
+					if code := status.Code(err); code == codes.Unavailable || code == codes.DeadlineExceeded {
+						n.Table.MarkSuspect(target.ID)
+					}
+					lastErr = err
+					continue
+				}
+				mu.Lock()
+				reached = append(reached, resp.Reached...)
+				mu.Unlock()
+				return
+			}
+			mu.Lock()
+			errs = append(errs, fmt.Errorf("no node at level %d towards %s acked: %w", b.level-1, b.candidates[0].ID, lastErr))
+			mu.Unlock()
+		}(b)
+	}
+
+	rooted := n.rootedObjects()
+
+	if n.AddNeighborSafe(newNode) {
+		n.migratePointers(ctx, rooted)
+
+		client, err := n.getClient(newNode)
+		if err == nil {
+			client.AddBackpointer(ctx, &pb.BackpointerRequest{
+				From:  n.toProtoNeighbor(),
+				Level: int32(id.SharedPrefixLength(n.ID, newNode.ID)),
+			})
+			client.Close()
+		}
+	}
+
+	wg.Wait()
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	return append(reached, n.toProtoNeighbor()), nil
+}
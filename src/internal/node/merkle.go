@@ -0,0 +1,89 @@
+package node
+
+import (
+	"crypto/sha1"
+	"fmt"
+	"sort"
+	"strings"
+
+	"tapestry/internal/id"
+)
+
+// The tree has a root, MERKLE_FANOUT inner nodes and MERKLE_FANOUT^2 leaf
+// buckets. An object lands in the leaf named by the first byte of its ID.
+const (
+	MERKLE_FANOUT = 16
+	MERKLE_LEAVES = MERKLE_FANOUT * MERKLE_FANOUT
+)
+
+type merkleTree struct {
+	root    []byte
+	inner   [MERKLE_FANOUT][]byte
+	leaves  [MERKLE_LEAVES][]byte
+	buckets [MERKLE_LEAVES]map[string][]byte // key -> object digest
+}
+
+func merkleBucket(key string) int {
+	return int(id.Hash(key)[0])
+}
+
+// objectDigest covers everything replicas must agree on: the versions held,
+// their contents and, for a block, the manifests that list it.
+func objectDigest(obj Object) []byte {
+	h := sha1.New()
+	for _, v := range obj.versions() {
+		h.Write([]byte(v.Version.String()))
+		if v.Deleted {
+			h.Write([]byte("deleted"))
+		}
+		h.Write(v.Data)
+		h.Write([]byte(strings.Join(v.Chunks, ",")))
+	}
+	manifests := make([]string, 0, len(obj.Refs))
+	for key, at := range obj.Refs {
+		manifests = append(manifests, fmt.Sprintf("%s@%d", key, at.UnixNano()))
+	}
+	sort.Strings(manifests)
+	h.Write([]byte(strings.Join(manifests, ",")))
+	return h.Sum(nil)
+}
+
+func buildMerkleTree(objects []Object) *merkleTree {
+	t := &merkleTree{}
+	for i := range t.buckets {
+		t.buckets[i] = make(map[string][]byte)
+	}
+	for _, obj := range objects {
+		t.buckets[merkleBucket(obj.Key)][obj.Key] = objectDigest(obj)
+	}
+
+	for i, bucket := range t.buckets {
+		keys := make([]string, 0, len(bucket))
+		for k := range bucket {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		h := sha1.New()
+		for _, k := range keys {
+			h.Write([]byte(k))
+			h.Write(bucket[k])
+		}
+		t.leaves[i] = h.Sum(nil)
+	}
+
+	for i := 0; i < MERKLE_FANOUT; i++ {
+		h := sha1.New()
+		for _, leaf := range t.leaves[i*MERKLE_FANOUT : (i+1)*MERKLE_FANOUT] {
+			h.Write(leaf)
+		}
+		t.inner[i] = h.Sum(nil)
+	}
+
+	h := sha1.New()
+	for _, in := range t.inner {
+		h.Write(in)
+	}
+	t.root = h.Sum(nil)
+	return t
+}
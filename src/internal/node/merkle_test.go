@@ -0,0 +1,50 @@
+package node
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+)
+
+func TestMerkleTree(t *testing.T) {
+	var objects []Object
+	for i := 0; i < 100; i++ {
+		objects = append(objects, Object{Key: fmt.Sprintf("key-%d", i), Data: []byte("v"), Version: VectorClock{"a": 1}})
+	}
+
+	a := buildMerkleTree(objects)
+	b := buildMerkleTree(append([]Object{}, objects...))
+	if !bytes.Equal(a.root, b.root) {
+		t.Fatalf("Identical object sets produced different roots")
+	}
+
+	changed := append([]Object{}, objects...)
+	changed[42] = Object{Key: "key-42", Data: []byte("v"), Version: VectorClock{"a": 2}}
+	c := buildMerkleTree(changed)
+	if bytes.Equal(a.root, c.root) {
+		t.Fatalf("A version change did not alter the root")
+	}
+
+	diffInner, diffLeaves := 0, 0
+	for i := range a.inner {
+		if !bytes.Equal(a.inner[i], c.inner[i]) {
+			diffInner++
+		}
+	}
+	for i := range a.leaves {
+		if !bytes.Equal(a.leaves[i], c.leaves[i]) {
+			diffLeaves++
+			if i != merkleBucket("key-42") {
+				t.Errorf("Leaf %d changed but key-42 lives in %d", i, merkleBucket("key-42"))
+			}
+		}
+	}
+	if diffInner != 1 || diffLeaves != 1 {
+		t.Errorf("Expected exactly one differing inner node and leaf, got %d and %d", diffInner, diffLeaves)
+	}
+
+	missing := buildMerkleTree(objects[1:])
+	if bytes.Equal(a.root, missing.root) {
+		t.Errorf("A missing object did not alter the root")
+	}
+}
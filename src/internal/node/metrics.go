@@ -0,0 +1,22 @@
+package node
+
+import "sync/atomic"
+
+// Metrics counts notable events on a node. Fields are updated atomically.
+type Metrics struct {
+	HintsQueued    int64 `json:"hintsQueued"`
+	HintsReplayed  int64 `json:"hintsReplayed"`
+	HintsExpired   int64 `json:"hintsExpired"`
+	HintsPending   int64 `json:"hintsPending"`
+	RouteFailovers int64 `json:"routeFailovers"` // Messages sent to a backup or surrogate after the next hop failed
+}
+
+func (n *Node) MetricsSnapshot() Metrics {
+	return Metrics{
+		HintsQueued:    atomic.LoadInt64(&n.Metrics.HintsQueued),
+		HintsReplayed:  atomic.LoadInt64(&n.Metrics.HintsReplayed),
+		HintsExpired:   atomic.LoadInt64(&n.Metrics.HintsExpired),
+		HintsPending:   int64(n.hints.Len()),
+		RouteFailovers: atomic.LoadInt64(&n.Metrics.RouteFailovers),
+	}
+}
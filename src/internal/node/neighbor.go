@@ -1,33 +1,49 @@
 package node
 
 import (
+	"crypto/ed25519"
+	"fmt"
 	"time"
 	pb "tapestry/api/proto"
 	"tapestry/internal/id"
 )
 
 type Neighbor struct {
-	ID      id.ID
-	Address string
-	Latency time.Duration
+	ID        id.ID
+	Address   string
+	Latency   time.Duration
+	PublicKey ed25519.PublicKey // Carried so the advertisement can be passed on
+	Signature []byte
 }
 
 func (n Neighbor) ToProto() *pb.Neighbor {
 	return &pb.Neighbor{
-		Id:      &pb.NodeID{Bytes: n.ID.Bytes()},
-		Address: n.Address,
+		Id:        &pb.NodeID{Bytes: n.ID.Bytes()},
+		Address:   n.Address,
+		PublicKey: n.PublicKey,
+		Signature: n.Signature,
 	}
 }
 
+// NeighborFromProto rejects advertisements whose ID is not derived from the
+// included public key or whose signature does not verify.
 func NeighborFromProto(p *pb.Neighbor) (Neighbor, error) {
+	if p == nil {
+		return Neighbor{}, fmt.Errorf("missing neighbor")
+	}
 	var rawID id.ID
 	if p.Id != nil {
 		copy(rawID[:], p.Id.Bytes)
 	}
+	if err := verifyNeighbor(rawID, p.Address, p.PublicKey, p.Signature); err != nil {
+		return Neighbor{}, err
+	}
     
 	return Neighbor{
-		ID:      rawID,
-		Address: p.Address,
-		Latency: 0,
+		ID:        rawID,
+		Address:   p.Address,
+		Latency:   0,
+		PublicKey: p.PublicKey,
+		Signature: p.Signature,
 	}, nil
 }
\ No newline at end of file
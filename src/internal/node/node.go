@@ -2,20 +2,27 @@ package node
 
 import (
 	"context"
+	"crypto/ed25519"
+	"crypto/tls"
 	"fmt"
 	"log"
 	"net"
+	"strconv"
 	"sync"
+	"sync/atomic"
 	"time"
 	"math/rand"
 
 	pb "tapestry/api/proto"
 	"tapestry/internal/id"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 )
 
 type PointerEntry struct {
 	Neighbor    Neighbor
+	Signature   []byte // Publisher's publish signature, forwarded on handoff
+	Timestamp   int64  // Signed with the publish; an older (un)publish is refused
 	LastUpdated time.Time
 }
 
@@ -23,6 +30,7 @@ type Node struct {
 	pb.UnimplementedNodeServiceServer
 
 	ID               id.ID
+	key              ed25519.PrivateKey // ID is the hash of its public half
 	Port             int
 	Address          string
 	GrpcServer       *grpc.Server
@@ -31,46 +39,221 @@ type Node struct {
 	Backpointers     map[string]Neighbor
 	bpLock           sync.RWMutex
 	LocationPointers map[id.ID][]*PointerEntry
+	withdrawn        map[id.ID]map[string]int64 // Unpublish timestamps, so replayed publishes stay refused
 	lpLock           sync.RWMutex
-	LocalObjects     map[id.ID]Object
-	objLock          sync.RWMutex
+	lastPublishStamp int64
+	Objects          Store
+	storeLock        sync.Mutex // Serializes read-modify-write of versioned objects
+	DataDir          string
+	WriteQuorum      int
+	ReadQuorum       int
+	ReadRepair       bool
+	TombstoneGrace   time.Duration
+	BlockGrace       time.Duration // How long a block's newest back-reference must stand before it is checked
+	Placement        PlacementPolicy
+	replicaHolders   map[string]map[string]Neighbor // key -> holder ID -> holder, for objects we own
+	rhLock           sync.RWMutex
+	rhDirty          bool // Holder sets changed since they were last saved
+	AntiEntropyInterval time.Duration
+	aeLimiter           *byteLimiter
+	hints               *HintQueue
+	replayingHints      int32 // Set while a background hint replay runs
+	Metrics             Metrics
+	pool                *ConnPool
+	Timeouts            Timeouts
+	MaxUploadSize       int64 // Largest body the HTTP API accepts for a publish
+	pointersHandedOff int64
 	stopChan     chan struct{} // For internal threads (maintenance)
 	ExitChan     chan struct{} // For main.go to know we are done
 	shutdownOnce sync.Once     // NEW: Ensure Stop() is idempotent
 }
 
+type Config struct {
+	Port                 int
+	ListenAddr           string             // host:port to bind; defaults to ":<Port>"
+	AdvertiseAddr        string             // host:port other nodes dial; defaults to the bind address
+	Key                  ed25519.PrivateKey // Defaults to a fresh key; the node ID is derived from it
+	ID                   id.ID              // If set, must match the ID derived from Key
+	DataDir              string             // Where the routing table is saved on shutdown, if set
+	Store                Store              // Defaults to an in-memory store
+	WriteQuorum          int                // Copies a put waits for; defaults to DEFAULT_WRITE_QUORUM
+	ReadQuorum           int                // Copies a get consults; defaults to DEFAULT_READ_QUORUM
+	ReadRepair           bool               // Repair lagging copies on every get
+	TombstoneGrace       time.Duration      // How long tombstones are kept; defaults to TOMBSTONE_GRACE_PERIOD
+	Placement            PlacementPolicy    // Defaults to SaltedRootPlacement
+	AntiEntropyInterval  time.Duration      // Defaults to ANTI_ENTROPY_INTERVAL
+	AntiEntropyBandwidth int64              // Bytes per second; 0 uses ANTI_ENTROPY_BANDWIDTH, negative is unlimited
+	TLS                  *tls.Config        // From LoadTLSConfig; enables mutual TLS for the server and outgoing connections
+	MaxConnections       int                // Defaults to CONN_POOL_MAX_SIZE
+	ConnIdleTimeout      time.Duration      // Defaults to CONN_IDLE_TIMEOUT
+	Timeouts             Timeouts           // Zero fields use the DEFAULT_*_TIMEOUT constants
+	MaxUploadSize        int64              // Bytes; defaults to DEFAULT_MAX_UPLOAD_SIZE
+}
+
 func NewNode(port int) (*Node, error) {
-	nodeID := id.NewRandomID()
-	address := fmt.Sprintf("localhost:%d", port)
+	return NewNodeWithConfig(Config{Port: port})
+}
+
+func NewNodeWithConfig(cfg Config) (*Node, error) {
+	key := cfg.Key
+	if key == nil {
+		_, generated, err := ed25519.GenerateKey(nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate node key: %w", err)
+		}
+		key = generated
+	}
+	nodeID := KeyID(key.Public().(ed25519.PublicKey))
+	if !cfg.ID.Equals(id.ZeroID) && !cfg.ID.Equals(nodeID) {
+		return nil, fmt.Errorf("node ID %s does not match the key's ID %s", cfg.ID, nodeID)
+	}
+	if cfg.TLS != nil {
+		if err := checkCertIdentity(cfg.TLS, nodeID); err != nil {
+			return nil, err
+		}
+	}
+
+	writeQuorum := cfg.WriteQuorum
+	if writeQuorum <= 0 {
+		writeQuorum = DEFAULT_WRITE_QUORUM
+	}
+	if writeQuorum > REPLICATION_FACTOR {
+		return nil, fmt.Errorf("write quorum %d exceeds replication factor %d", writeQuorum, REPLICATION_FACTOR)
+	}
+
+	readQuorum := cfg.ReadQuorum
+	if readQuorum <= 0 {
+		readQuorum = DEFAULT_READ_QUORUM
+	}
+	if readQuorum > REPLICATION_FACTOR {
+		return nil, fmt.Errorf("read quorum %d exceeds replication factor %d", readQuorum, REPLICATION_FACTOR)
+	}
 
-	listener, err := net.Listen("tcp", fmt.Sprintf(":%d", port))
+	hints, err := NewHintQueue(cfg.DataDir)
 	if err != nil {
-		return nil, fmt.Errorf("failed to listen on port %d: %w", port, err)
+		return nil, err
+	}
+
+	holders := make(map[string]map[string]Neighbor)
+	if cfg.DataDir != "" {
+		if holders, err = loadReplicaHolders(cfg.DataDir); err != nil {
+			return nil, err
+		}
+	}
+
+	listenAddr := cfg.ListenAddr
+	if listenAddr == "" {
+		listenAddr = fmt.Sprintf(":%d", cfg.Port)
+	}
+
+	listener, err := net.Listen("tcp", listenAddr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen on %s: %w", listenAddr, err)
+	}
+	port := listener.Addr().(*net.TCPAddr).Port
+
+	address := cfg.AdvertiseAddr
+	if address == "" {
+		address = defaultAdvertiseAddr(listenAddr, port)
+	}
+
+	store := cfg.Store
+	if store == nil {
+		store = NewMemoryStore()
+	}
+
+	placement := cfg.Placement
+	if placement == nil {
+		placement = NewSaltedRootPlacement()
+	}
+
+	tombstoneGrace := cfg.TombstoneGrace
+	if tombstoneGrace <= 0 {
+		tombstoneGrace = TOMBSTONE_GRACE_PERIOD
+	}
+
+	aeInterval := cfg.AntiEntropyInterval
+	if aeInterval <= 0 {
+		aeInterval = ANTI_ENTROPY_INTERVAL
+	}
+	maxUpload := cfg.MaxUploadSize
+	if maxUpload <= 0 {
+		maxUpload = DEFAULT_MAX_UPLOAD_SIZE
+	}
+
+	aeBandwidth := cfg.AntiEntropyBandwidth
+	if aeBandwidth == 0 {
+		aeBandwidth = ANTI_ENTROPY_BANDWIDTH
+	}
+
+	var serverOpts []grpc.ServerOption
+	var clientCreds credentials.TransportCredentials
+	if cfg.TLS != nil {
+		serverOpts = append(serverOpts, grpc.Creds(credentials.NewTLS(serverTLSConfig(cfg.TLS))))
+		clientCreds = ClientCredentials(cfg.TLS)
 	}
 
 	n := &Node{
 		ID:               nodeID,
+		key:              key,
 		Port:             port,
 		Address:          address,
-		GrpcServer:       grpc.NewServer(),
+		GrpcServer:       grpc.NewServer(serverOpts...),
 		Listener:         listener,
 		Table:            NewRoutingTable(nodeID),
 		Backpointers:     make(map[string]Neighbor),
 		LocationPointers: make(map[id.ID][]*PointerEntry),
-		LocalObjects:     make(map[id.ID]Object),
+		withdrawn:        make(map[id.ID]map[string]int64),
+		Objects:          store,
+		replicaHolders:   holders,
+		DataDir:          cfg.DataDir,
+		WriteQuorum:      writeQuorum,
+		ReadQuorum:       readQuorum,
+		ReadRepair:       cfg.ReadRepair,
+		TombstoneGrace:   tombstoneGrace,
+		BlockGrace:       BLOCK_GRACE_PERIOD,
+		Placement:        placement,
+		AntiEntropyInterval: aeInterval,
+		aeLimiter:           &byteLimiter{rate: aeBandwidth},
+		hints:               hints,
+		Timeouts:            cfg.Timeouts.withDefaults(),
+		MaxUploadSize:       maxUpload,
 		stopChan:         make(chan struct{}),
 		ExitChan:         make(chan struct{}),
 	}
 
+	n.pool = NewConnPool(ConnPoolConfig{
+		MaxSize:            cfg.MaxConnections,
+		IdleTimeout:        cfg.ConnIdleTimeout,
+		Creds:              clientCreds,
+		OnTransientFailure: n.connectionFailed,
+	})
+
 	pb.RegisterNodeServiceServer(n.GrpcServer, n)
-	log.Printf("Created Node %s at %s", n.ID.String(), n.Address)
+	log.Printf("Created Node %s at %s (listening on %s)", n.ID.String(), n.Address, listener.Addr())
 
 	return n, nil
 }
 
+// defaultAdvertiseAddr reuses the bind host unless it is a wildcard, in which
+// case only localhost is known to reach us.
+func defaultAdvertiseAddr(listenAddr string, port int) string {
+	host, _, err := net.SplitHostPort(listenAddr)
+	if err != nil || host == "" || host == "0.0.0.0" || host == "::" {
+		host = "localhost"
+	}
+	return net.JoinHostPort(host, strconv.Itoa(port))
+}
+
 func (n *Node) Start() error {
 	go n.StartMaintenanceLoop()
 	go n.StartRepublishLoop()
+	go n.StartAntiEntropyLoop()
+	go n.StartRepairLoop()
+	if count := n.Objects.Len(); count > 0 {
+		log.Printf("Restored %d objects from storage. Republishing...", count)
+		go n.republishObjects()
+	}
 	log.Printf("Starting gRPC server on %s", n.Address)
 	return n.GrpcServer.Serve(n.Listener)
 }
@@ -78,9 +261,26 @@ func (n *Node) Start() error {
 func (n *Node) Stop() {
 	n.shutdownOnce.Do(func() {
 		close(n.stopChan)
+		if n.DataDir != "" {
+			if err := n.SaveRoutingTable(n.DataDir); err != nil {
+				log.Printf("Failed to save routing table: %v", err)
+			}
+			if err := n.SaveReplicaHolders(n.DataDir); err != nil {
+				log.Printf("Failed to save replica holders: %v", err)
+			}
+		}
 		if n.GrpcServer != nil {
 			n.GrpcServer.GracefulStop()
 		}
+		if n.pool != nil {
+			n.pool.Close()
+		}
+		if err := n.Objects.Close(); err != nil {
+			log.Printf("Failed to close object store: %v", err)
+		}
+		if err := n.hints.Close(); err != nil {
+			log.Printf("Failed to close hint log: %v", err)
+		}
 	})
 }
 
@@ -118,14 +318,40 @@ func (n *Node) SelectRandomNeighbors(count int) []Neighbor {
 }
 
 
+// getClient dials nb through the node's pool and, over TLS, checks that the
+// server is nb. A neighbor with a zero ID, such as a bootstrap address, is
+// only checked against the mesh CA. Nodes built without a pool, as in unit
+// tests, share the default pool.
+func (n *Node) getClient(nb Neighbor) (*TapestryClient, error) {
+	if n.pool == nil {
+		return defaultPool.GetNode(nb.Address, nb.ID)
+	}
+	return n.pool.GetNode(nb.Address, nb.ID)
+}
+
+// connectionFailed has routing skip neighbors at address as soon as their
+// connection fails. They stay in the table, so a brief restart does not
+// evict them; the next keepalive either clears them or removes them.
+func (n *Node) connectionFailed(address string) {
+	for _, nb := range n.Table.SuspectAddress(address) {
+		log.Printf("[POOL] Connection to %s failed. Marked %s suspect.", address, nb.ID)
+	}
+}
+
 func (n *Node) Probe(address string) (time.Duration, error) {
+	return n.probe(context.Background(), Neighbor{Address: address})
+}
+
+func (n *Node) probe(ctx context.Context, nb Neighbor) (time.Duration, error) {
 	start := time.Now()
-	client, err := GetClient(address)
+	client, err := n.getClient(nb)
 	if err != nil {
 		return 0, err
 	}
 	defer client.Close()
-	_, err = client.Ping(context.Background(), &pb.Nothing{})
+	ctx, cancel := n.rpcContext(ctx)
+	defer cancel()
+	_, err = client.Ping(ctx, &pb.Nothing{})
 	if err != nil {
 		return 0, err
 	}
@@ -133,7 +359,7 @@ func (n *Node) Probe(address string) (time.Duration, error) {
 }
 
 func (n *Node) AddNeighborSafe(nb Neighbor) bool {
-	rtt, err := n.Probe(nb.Address)
+	rtt, err := n.probe(context.Background(), nb)
 	if err != nil {
 		return false
 	}
@@ -142,13 +368,15 @@ func (n *Node) AddNeighborSafe(nb Neighbor) bool {
 }
 
 func (n *Node) GetLocalObjectCount() int {
-	n.objLock.RLock()
-	defer n.objLock.RUnlock()
-	return len(n.LocalObjects)
+	return n.Objects.Len()
 }
 
 func (n *Node) GetLocationPointerCount() int {
 	n.lpLock.RLock()
 	defer n.lpLock.RUnlock()
 	return len(n.LocationPointers)
+}
+
+func (n *Node) GetHandedOffPointerCount() int {
+	return int(atomic.LoadInt64(&n.pointersHandedOff))
 }
\ No newline at end of file
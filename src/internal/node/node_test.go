@@ -0,0 +1,56 @@
+package node
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestDefaultAdvertiseAddr(t *testing.T) {
+	cases := map[string]string{
+		":7000":         "localhost:7000",
+		"0.0.0.0:7000":  "localhost:7000",
+		"10.0.0.5:7000": "10.0.0.5:7000",
+		"node-a:7000":   "node-a:7000",
+	}
+	for listen, expected := range cases {
+		if got := defaultAdvertiseAddr(listen, 7000); got != expected {
+			t.Errorf("defaultAdvertiseAddr(%q) = %q, expected %q", listen, got, expected)
+		}
+	}
+}
+
+func TestNewNodeWithConfigAdvertise(t *testing.T) {
+	n, err := NewNodeWithConfig(Config{
+		ListenAddr:    "127.0.0.1:0",
+		AdvertiseAddr: "node-a.internal:7000",
+	})
+	if err != nil {
+		t.Fatalf("Failed to create node: %v", err)
+	}
+	defer n.Listener.Close()
+
+	if n.Port == 0 {
+		t.Errorf("Port should be resolved from the listener")
+	}
+	if n.Address != "node-a.internal:7000" {
+		t.Errorf("Expected advertised address, got %s", n.Address)
+	}
+	if got := n.toProtoNeighbor().Address; got != "node-a.internal:7000" {
+		t.Errorf("Neighbor proto carries %s instead of the advertised address", got)
+	}
+}
+
+func TestPublishHandlerRejectsOversizedBody(t *testing.T) {
+	n := &Node{MaxUploadSize: 1024}
+	body := bytes.NewReader(make([]byte, 1025))
+	req := httptest.NewRequest(http.MethodPost, "/publish?key=big", body)
+	req.Header.Set("Content-Type", "application/octet-stream")
+	rec := httptest.NewRecorder()
+
+	n.publishHandler(rec, req)
+	if rec.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("Oversized publish answered %d, expected %d", rec.Code, http.StatusRequestEntityTooLarge)
+	}
+}
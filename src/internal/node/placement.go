@@ -0,0 +1,118 @@
+package node
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"tapestry/internal/id"
+)
+
+const (
+	PLACEMENT_EXTRA_SALTS     = 32 // Extra salts tried when several salted IDs share a root
+	PLACEMENT_CACHE_TTL       = 30 * time.Second
+	PLACEMENT_SHORT_CACHE_TTL = 5 * time.Second // For sets short of count, which a failed trace may explain
+	PLACEMENT_CACHE_SIZE      = 4096
+)
+
+// PlacementPolicy decides which nodes should hold the copies of an object.
+// The returned set may include n itself.
+type PlacementPolicy interface {
+	Replicas(n *Node, key string, count int) []Neighbor
+}
+
+// RandomPlacement keeps a copy on the writer and spreads the rest over
+// random routing table entries.
+type RandomPlacement struct{}
+
+func (RandomPlacement) Replicas(n *Node, key string, count int) []Neighbor {
+	self := Neighbor{ID: n.ID, Address: n.Address}
+	return append([]Neighbor{self}, n.SelectRandomNeighbors(count-1)...)
+}
+
+// SaltedRootPlacement stores copies on the roots of the salted IDs the object
+// is published under, so every node computes the same set for a key. Sets
+// are cached for PLACEMENT_CACHE_TTL, since each one costs a route trace per
+// salt and is wanted on every put, delete and repair pass. A short set may be
+// down to a failed trace, or to a mesh with fewer roots than count, so it is
+// kept only for PLACEMENT_SHORT_CACHE_TTL.
+type SaltedRootPlacement struct {
+	cache map[string]placementEntry
+	lock  sync.Mutex
+}
+
+type placementEntry struct {
+	replicas []Neighbor
+	expires  time.Time
+}
+
+func NewSaltedRootPlacement() *SaltedRootPlacement {
+	return &SaltedRootPlacement{cache: make(map[string]placementEntry)}
+}
+
+func (p *SaltedRootPlacement) Replicas(n *Node, key string, count int) []Neighbor {
+	cacheKey := fmt.Sprintf("%d/%s", count, key)
+	p.lock.Lock()
+	entry, ok := p.cache[cacheKey]
+	p.lock.Unlock()
+	if ok && time.Now().Before(entry.expires) {
+		return append([]Neighbor(nil), entry.replicas...)
+	}
+
+	seen := make(map[string]bool)
+	var replicas []Neighbor
+
+	for i := 0; len(replicas) < count && i < count+PLACEMENT_EXTRA_SALTS; i++ {
+		root, err := n.FindRoot(id.Hash(fmt.Sprintf("%s-%d", key, i)))
+		if err != nil || seen[root.ID.String()] {
+			continue
+		}
+		seen[root.ID.String()] = true
+		replicas = append(replicas, root)
+	}
+
+	ttl := PLACEMENT_CACHE_TTL
+	if len(replicas) < count {
+		ttl = PLACEMENT_SHORT_CACHE_TTL
+	}
+	if len(replicas) > 0 {
+		p.store(cacheKey, append([]Neighbor(nil), replicas...), ttl)
+	}
+	return replicas
+}
+
+func (p *SaltedRootPlacement) store(cacheKey string, replicas []Neighbor, ttl time.Duration) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if p.cache == nil {
+		p.cache = make(map[string]placementEntry)
+	}
+	now := time.Now()
+	if len(p.cache) >= PLACEMENT_CACHE_SIZE {
+		for k, e := range p.cache {
+			if now.After(e.expires) {
+				delete(p.cache, k)
+			}
+		}
+		if len(p.cache) >= PLACEMENT_CACHE_SIZE {
+			p.cache = make(map[string]placementEntry)
+		}
+	}
+	p.cache[cacheKey] = placementEntry{replicas: replicas, expires: now.Add(ttl)}
+}
+
+func PlacementByName(name string) (PlacementPolicy, error) {
+	switch name {
+	case "random":
+		return RandomPlacement{}, nil
+	case "salted-root":
+		return NewSaltedRootPlacement(), nil
+	}
+	return nil, fmt.Errorf("unknown placement policy %q", name)
+}
+
+// ReplicaSet returns the nodes that should hold key under this node's
+// placement policy.
+func (n *Node) ReplicaSet(key string) []Neighbor {
+	return n.Placement.Replicas(n, key, REPLICATION_FACTOR)
+}
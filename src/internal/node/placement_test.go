@@ -0,0 +1,28 @@
+package node
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSaltedRootPlacementShortSet(t *testing.T) {
+	n := newSigningNode(t, "local")
+	n.Table = NewRoutingTable(n.ID)
+	localID := n.ID
+	p := NewSaltedRootPlacement()
+
+	// A lone node is the only root there is, so the set can never be full.
+	replicas := p.Replicas(n, "k", REPLICATION_FACTOR)
+	if len(replicas) != 1 || !replicas[0].ID.Equals(localID) {
+		t.Fatalf("Expected the lone node as the only replica, got %v", replicas)
+	}
+
+	if len(p.cache) != 1 {
+		t.Fatalf("Short set was not cached")
+	}
+	for _, entry := range p.cache {
+		if ttl := time.Until(entry.expires); ttl > PLACEMENT_SHORT_CACHE_TTL {
+			t.Errorf("Short set cached for %v, expected at most %v", ttl, PLACEMENT_SHORT_CACHE_TTL)
+		}
+	}
+}
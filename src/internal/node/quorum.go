@@ -0,0 +1,25 @@
+package node
+
+import "time"
+
+const (
+	DEFAULT_WRITE_QUORUM = 1
+	DEFAULT_READ_QUORUM  = 1 // The first copy found
+)
+
+// WriteOptions controls how many copies of an object must be stored before a
+// put returns. Zero uses the node default. A positive TTL makes the object
+// expire that long after the write.
+type WriteOptions struct {
+	W   int
+	TTL time.Duration
+}
+
+// ReadOptions controls how many copies of an object a get consults. Zero uses
+// the node default. A get is only sure to see the latest put when R and W
+// add up to more than REPLICATION_FACTOR. ReadRepair consults every
+// publisher and refreshes the ones that lag behind.
+type ReadOptions struct {
+	R          int
+	ReadRepair bool
+}
@@ -0,0 +1,45 @@
+package node
+
+import (
+	"bytes"
+	"context"
+	"log"
+)
+
+type publisherCopy struct {
+	holder Neighbor
+	obj    Object
+	found  bool
+}
+
+// readRepair pushes latest to every publisher whose copy was missing or
+// differed from it.
+func (n *Node) readRepair(latest Object, copies []publisherCopy) {
+	want := objectDigest(latest)
+	for _, c := range copies {
+		if c.found && bytes.Equal(objectDigest(c.obj), want) {
+			continue
+		}
+
+		if c.holder.ID.Equals(n.ID) {
+			if err := n.storeLocal(latest); err != nil {
+				log.Printf("[READ-REPAIR] Failed to refresh local copy of '%s': %v", latest.Key, err)
+			}
+			continue
+		}
+
+		client, err := n.getClient(c.holder)
+		if err != nil {
+			continue
+		}
+		ctx, cancel := n.rpcContext(context.Background())
+		_, err = client.Replicate(ctx, latest.toReplicateRequest())
+		cancel()
+		client.Close()
+		if err != nil {
+			log.Printf("[READ-REPAIR] Failed to refresh '%s' on %s: %v", latest.Key, c.holder.Address, err)
+			continue
+		}
+		log.Printf("[READ-REPAIR] Refreshed '%s' on %s", latest.Key, c.holder.Address)
+	}
+}
@@ -1,6 +1,7 @@
 package node
 
 import (
+	"context"
 	"log"
 	"time"
 )
@@ -22,6 +23,9 @@ func (n *Node) StartMaintenanceLoop() {
 		case <-ticker.C:
 			n.runKeepAlives()
 			n.runPointerGC()
+			n.expireHints()
+			n.CollectTombstones()
+			n.SweepExpired()
 		}
 	}
 }
@@ -50,11 +54,23 @@ func (n *Node) runKeepAlives() {
 	}
 	n.Table.lock.RUnlock()
 
+	// A neighbor is only removed once it was already suspect, so a single
+	// missed ping or dropped connection does not evict it.
+	alive := make(map[string]bool)
 	for _, nb := range neighbors {
-		_, err := n.Probe(nb.Address)
-		if err != nil {
-			log.Printf("[REPAIR] Neighbor %s unreachable. Removing.", nb.Address)
+		suspect := n.Table.IsSuspect(nb.ID)
+		_, err := n.probe(context.Background(), nb)
+		if err != nil && suspect {
+			log.Printf("[REPAIR] Suspect neighbor %s still unreachable. Removing.", nb.Address)
 			n.Table.Remove(nb.ID)
+		} else if err != nil {
+			log.Printf("[REPAIR] Neighbor %s unreachable. Marking suspect.", nb.Address)
+			n.Table.MarkSuspect(nb.ID)
+		} else {
+			if n.Table.ClearSuspect(nb.ID) {
+				log.Printf("[REPAIR] Suspect neighbor %s is reachable again.", nb.Address)
+			}
+			alive[nb.ID.String()] = true
 		}
 	}
 
@@ -66,14 +82,18 @@ func (n *Node) runKeepAlives() {
 	n.bpLock.RUnlock()
 
 	for _, bp := range bps {
-		_, err := n.Probe(bp.Address)
+		_, err := n.probe(context.Background(), bp)
 		if err != nil {
 			log.Printf("[REPAIR] Backpointer %s unreachable. Removing.", bp.Address)
 			n.bpLock.Lock()
 			delete(n.Backpointers, bp.ID.String())
 			n.bpLock.Unlock()
+		} else {
+			alive[bp.ID.String()] = true
 		}
 	}
+
+	n.replayHintsInBackground(alive)
 }
 
 func (n *Node) runPointerGC() {
@@ -96,17 +116,30 @@ func (n *Node) runPointerGC() {
 			delete(n.LocationPointers, key)
 		}
 	}
+
+	// Publishes older than PUBLISH_MAX_AGE, plus the skew margin, are refused
+	// anyway.
+	for objID, stamps := range n.withdrawn {
+		for publisher, stamp := range stamps {
+			if time.Since(time.Unix(0, stamp)) > PUBLISH_MAX_AGE+PUBLISH_CLOCK_SKEW {
+				delete(stamps, publisher)
+			}
+		}
+		if len(stamps) == 0 {
+			delete(n.withdrawn, objID)
+		}
+	}
 }
 
 func (n *Node) republishObjects() {
-	n.objLock.RLock()
 	var keys []string
-	for _, obj := range n.LocalObjects {
-		keys = append(keys, obj.Key)
+	for _, obj := range n.Objects.List() {
+		if !obj.Expired() {
+			keys = append(keys, obj.Key)
+		}
 	}
-	n.objLock.RUnlock()
 
 	for _, key := range keys {
-		go n.publishSelfSalted(key)
+		go n.publishSelfSalted(context.Background(), key)
 	}
 }
\ No newline at end of file
@@ -0,0 +1,267 @@
+package node
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+
+	"tapestry/internal/id"
+)
+
+const (
+	HOLDERS_FILE_NAME = "replica_holders.json"
+	REPAIR_INTERVAL   = 20 * time.Second
+)
+
+func (n *Node) trackHolder(key string, holder Neighbor) {
+	n.rhLock.Lock()
+	defer n.rhLock.Unlock()
+	if n.replicaHolders[key] == nil {
+		n.replicaHolders[key] = make(map[string]Neighbor)
+	}
+	n.replicaHolders[key][holder.ID.String()] = holder
+	n.rhDirty = true
+}
+
+func (n *Node) forgetHolders(key string) {
+	n.rhLock.Lock()
+	delete(n.replicaHolders, key)
+	n.rhDirty = true
+	n.rhLock.Unlock()
+}
+
+func (n *Node) forgetHolder(key string, holder Neighbor) {
+	n.rhLock.Lock()
+	delete(n.replicaHolders[key], holder.ID.String())
+	n.rhDirty = true
+	n.rhLock.Unlock()
+}
+
+func (n *Node) holdersOf(key string) []Neighbor {
+	n.rhLock.RLock()
+	defer n.rhLock.RUnlock()
+	var holders []Neighbor
+	for _, h := range n.replicaHolders[key] {
+		holders = append(holders, h)
+	}
+	return holders
+}
+
+// SaveReplicaHolders writes the holder sets of the objects this node owns,
+// so a restart does not take every object for under-replicated.
+func (n *Node) SaveReplicaHolders(dir string) error {
+	n.rhLock.Lock()
+	saved := make(map[string][]savedNeighbor, len(n.replicaHolders))
+	for key, holders := range n.replicaHolders {
+		for _, h := range holders {
+			saved[key] = append(saved[key], savedNeighbor{ID: h.ID.String(), Address: h.Address})
+		}
+	}
+	n.rhDirty = false
+	n.rhLock.Unlock()
+
+	data, err := json.Marshal(saved)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	path := filepath.Join(dir, HOLDERS_FILE_NAME)
+	if err := os.WriteFile(path+".tmp", data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(path+".tmp", path)
+}
+
+func loadReplicaHolders(dir string) (map[string]map[string]Neighbor, error) {
+	holders := make(map[string]map[string]Neighbor)
+	data, err := os.ReadFile(filepath.Join(dir, HOLDERS_FILE_NAME))
+	if os.IsNotExist(err) {
+		return holders, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var saved map[string][]savedNeighbor
+	if err := json.Unmarshal(data, &saved); err != nil {
+		return nil, fmt.Errorf("corrupt replica holder snapshot: %w", err)
+	}
+	for key, list := range saved {
+		for _, s := range list {
+			hID, err := id.Parse(s.ID)
+			if err != nil {
+				continue
+			}
+			if holders[key] == nil {
+				holders[key] = make(map[string]Neighbor)
+			}
+			holders[key][s.ID] = Neighbor{ID: hID, Address: s.Address}
+		}
+	}
+	return holders, nil
+}
+
+func (n *Node) StartRepairLoop() {
+	ticker := time.NewTicker(REPAIR_INTERVAL)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-n.stopChan:
+			return
+		case <-ticker.C:
+			n.RepairReplicas()
+			n.CollectBlocks()
+			n.saveReplicaHoldersIfDirty()
+		}
+	}
+}
+
+func (n *Node) saveReplicaHoldersIfDirty() {
+	n.rhLock.RLock()
+	dirty := n.rhDirty
+	n.rhLock.RUnlock()
+	if !dirty || n.DataDir == "" {
+		return
+	}
+	if err := n.SaveReplicaHolders(n.DataDir); err != nil {
+		log.Printf("[REPAIR] Failed to save replica holders: %v", err)
+	}
+}
+
+// RepairReplicas checks every object this node owns and re-replicates those
+// with fewer than REPLICATION_FACTOR reachable copies. A holder whose owner
+// has left the mesh takes ownership if it is the first reachable member of
+// the object's replica set, or if no member is reachable; otherwise it hands
+// its copy to that member, which takes over on its own pass. It returns the
+// number of new replicas created.
+func (n *Node) RepairReplicas() int {
+	alive := make(map[string]bool)
+	reachable := func(nb Neighbor) bool {
+		if ok, probed := alive[nb.Address]; probed {
+			return ok
+		}
+		_, err := n.probe(context.Background(), nb)
+		alive[nb.Address] = err == nil
+		return err == nil
+	}
+
+	ownerGone := make(map[string]bool)
+	created := 0
+	for _, obj := range n.Objects.List() {
+		if obj.Expired() {
+			continue
+		}
+		if obj.Owner != n.ID.String() {
+			gone, checked := ownerGone[obj.Owner]
+			if !checked {
+				gone = n.ownerLeft(obj.Owner)
+				ownerGone[obj.Owner] = gone
+			}
+			if !gone || !n.takeOver(obj, reachable) {
+				continue
+			}
+			obj.Owner = n.ID.String()
+		}
+
+		have := map[string]bool{n.ID.String(): true}
+		for _, h := range n.holdersOf(obj.Key) {
+			if reachable(h) {
+				have[h.ID.String()] = true
+				continue
+			}
+			log.Printf("[REPAIR] Replica holder %s of '%s' is unreachable", h.Address, obj.Key)
+			n.forgetHolder(obj.Key, h)
+		}
+		if len(have) >= REPLICATION_FACTOR {
+			continue
+		}
+
+		candidates := append(n.ReplicaSet(obj.Key), n.SelectRandomNeighbors(REPLICATION_FACTOR)...)
+		for _, c := range candidates {
+			if len(have) >= REPLICATION_FACTOR {
+				break
+			}
+			if have[c.ID.String()] || !reachable(c) {
+				continue
+			}
+			if n.sendReplica(c, obj) {
+				have[c.ID.String()] = true
+				created++
+			}
+		}
+
+		if len(have) < REPLICATION_FACTOR {
+			log.Printf("[REPAIR] '%s' still has only %d/%d copies", obj.Key, len(have), REPLICATION_FACTOR)
+		}
+	}
+
+	if created > 0 {
+		log.Printf("[REPAIR] Created %d new replicas", created)
+	}
+	return created
+}
+
+// ownerLeft reports whether routing to owner ends at some other node, which
+// only happens once the owner has left the mesh. A failed trace proves
+// nothing.
+func (n *Node) ownerLeft(owner string) bool {
+	ownerID, err := id.Parse(owner)
+	if err != nil {
+		return true
+	}
+	root, err := n.FindRoot(ownerID)
+	return err == nil && !root.ID.Equals(ownerID)
+}
+
+// takeOver decides whether this holder becomes the owner of obj. If another
+// member of the replica set comes first, it is given a copy instead. Holders
+// may disagree on the order of the set while routing settles, so a holder
+// only defers to members with a lower ID; otherwise two holders could keep
+// handing the object to each other.
+func (n *Node) takeOver(obj Object, reachable func(Neighbor) bool) bool {
+	for _, member := range n.ReplicaSet(obj.Key) {
+		if member.ID.Equals(n.ID) {
+			break
+		}
+		if member.ID.String() == obj.Owner || bytes.Compare(member.ID.Bytes(), n.ID.Bytes()) > 0 || !reachable(member) {
+			continue
+		}
+		n.sendReplica(member, obj)
+		return false
+	}
+
+	obj.Owner = n.ID.String()
+	if err := n.storeLocal(obj); err != nil {
+		log.Printf("[REPAIR] Failed to take over '%s': %v", obj.Key, err)
+		return false
+	}
+	log.Printf("[REPAIR] Owner of '%s' left. Taking over its replicas.", obj.Key)
+	return true
+}
+
+func (n *Node) sendReplica(target Neighbor, obj Object) bool {
+	client, err := n.getClient(target)
+	if err != nil {
+		return false
+	}
+	defer client.Close()
+
+	ctx, cancel := n.rpcContext(context.Background())
+	defer cancel()
+	if _, err := client.Replicate(ctx, obj.toReplicateRequest()); err != nil {
+		log.Printf("[REPAIR] Failed to replicate '%s' to %s: %v", obj.Key, target.Address, err)
+		return false
+	}
+	if obj.Owner == n.ID.String() {
+		n.trackHolder(obj.Key, target)
+	}
+	return true
+}
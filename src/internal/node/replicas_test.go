@@ -0,0 +1,37 @@
+package node
+
+import (
+	"testing"
+
+	"tapestry/internal/id"
+)
+
+func TestReplicaHolderSnapshot(t *testing.T) {
+	dir := t.TempDir()
+	n := &Node{replicaHolders: make(map[string]map[string]Neighbor)}
+
+	holder := Neighbor{ID: id.Hash("holder"), Address: "localhost:9999"}
+	n.trackHolder("kept", holder)
+	if !n.rhDirty {
+		t.Errorf("Tracking a holder did not mark the holder sets dirty")
+	}
+
+	if err := n.SaveReplicaHolders(dir); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	if n.rhDirty {
+		t.Errorf("Saving did not clear the dirty flag")
+	}
+	holders, err := loadReplicaHolders(dir)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	got, ok := holders["kept"][holder.ID.String()]
+	if !ok || got.Address != holder.Address {
+		t.Errorf("Unexpected snapshot contents: %+v", holders)
+	}
+
+	if holders, err := loadReplicaHolders(t.TempDir()); err != nil || len(holders) != 0 {
+		t.Errorf("Missing snapshot should load as empty, got %v (%v)", holders, err)
+	}
+}
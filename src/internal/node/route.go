@@ -3,11 +3,19 @@ package node
 import (
 	"context"
 	"fmt"
+	"log"
+	"sync/atomic"
 
 	pb "tapestry/api/proto"
 	"tapestry/internal/id"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
+// Next hops tried for one message before giving up: the primary, its
+// backups, and as many surrogates again.
+const MAX_ROUTE_ATTEMPTS = 2 * (1 + K_BACKUPS)
+
 func (n *Node) GetNextHop(ctx context.Context, req *pb.RouteRequest) (*pb.RouteResponse, error) {
 	var targetID id.ID
 	if len(req.TargetId.Bytes) != id.BYTES {
@@ -23,39 +31,122 @@ func (n *Node) GetNextHop(ctx context.Context, req *pb.RouteRequest) (*pb.RouteR
 	}, nil
 }
 
+type routeDecision struct {
+	NextHop   Neighbor
+	IsRoot    bool
+	Level     int
+	Surrogate bool
+}
+
 func (n *Node) computeNextHop(target id.ID) (Neighbor, bool) {
+	d := n.computeRoute(target)
+	return d.NextHop, d.IsRoot
+}
+
+func (n *Node) computeRoute(target id.ID) routeDecision {
+	return n.resolveRoute(target, false)
+}
+
+// computeNextHopExcludingSelf routes as if this node had already left the
+// network, which yields the first hop towards the root that will take over.
+func (n *Node) computeNextHopExcludingSelf(target id.ID) (Neighbor, bool) {
+	d := n.resolveRoute(target, true)
+	return d.NextHop, d.IsRoot
+}
+
+func (n *Node) resolveRoute(target id.ID, excludeSelf bool) routeDecision {
 	n.Table.lock.RLock()
 	defer n.Table.lock.RUnlock()
 
-	if n.ID.Equals(target) {
-		return Neighbor{ID: n.ID, Address: n.Address}, true
-	}
-
+	self := Neighbor{ID: n.ID, Address: n.Address}
 	level := id.SharedPrefixLength(n.ID, target)
 
-	if level >= id.DIGITS {
-		return Neighbor{ID: n.ID, Address: n.Address}, true
+	// Without us, our own digit at a level is only occupied if some other
+	// node shares a longer prefix with us, i.e. sits in a deeper row.
+	deepest := -1
+	if excludeSelf {
+		deepest = n.Table.deepestRow()
+		if deepest < 0 {
+			return routeDecision{NextHop: self, IsRoot: true, Level: level}
+		}
+		if level > deepest {
+			level = deepest
+		}
+	} else if level >= id.DIGITS {
+		return routeDecision{NextHop: self, IsRoot: true, Level: level}
 	}
 
-	desiredDigit := target.GetDigit(level)
+	// Surrogate routing: when the desired slot is empty, take the next
+	// non-empty digit. If that digit is our own, we fill the slot ourselves
+	// and keep resolving at the next level, so every node holding a
+	// consistent table converges on the same root.
+	surrogate := false
+	for ; level < id.DIGITS; level++ {
+		desiredDigit := target.GetDigit(level)
+		ownDigit := n.ID.GetDigit(level)
+
+		for offset := 0; offset < id.RADIX; offset++ {
+			digit := (desiredDigit + offset) % id.RADIX
+			if digit == ownDigit {
+				if excludeSelf && level >= deepest {
+					continue
+				}
+				break
+			}
+
+			// Suspect primaries give way to their backups, and a slot with
+			// only suspects is treated as empty.
+			if nb, ok := n.Table.firstLive(level, digit); ok {
+				return routeDecision{NextHop: nb, Level: level, Surrogate: surrogate || offset > 0}
+			}
+		}
 
-	primaryCandidates := n.Table.rows[level][desiredDigit]
-	if len(primaryCandidates) > 0 {
-		return primaryCandidates[0], false
+		if ownDigit != desiredDigit {
+			surrogate = true
+		}
 	}
 
-	for offset := 1; offset < id.RADIX; offset++ {
-		surrogateDigit := (desiredDigit + offset) % id.RADIX
-		
-		if n.ID.GetDigit(level) == surrogateDigit {
-			return Neighbor{ID: n.ID, Address: n.Address}, true
+	return routeDecision{NextHop: self, IsRoot: true, Level: id.DIGITS, Surrogate: surrogate}
+}
+
+// forward sends a routed message one hop towards target. A next hop that is
+// unreachable or does not answer within its share of the budget is marked
+// suspect and the route recomputed, which falls back to the slot's backups
+// and then to surrogate digits. If every candidate is suspect we become the
+// root ourselves, and forward returns a decision with IsRoot set without
+// sending anything.
+func (n *Node) forward(ctx context.Context, target id.ID, send func(context.Context, *TapestryClient, routeDecision) error) (routeDecision, error) {
+	var err error
+	for attempt := 0; attempt < MAX_ROUTE_ATTEMPTS; attempt++ {
+		d := n.computeRoute(target)
+		if d.IsRoot || d.NextHop.ID.Equals(n.ID) {
+			return d, nil
+		}
+		if attempt > 0 {
+			atomic.AddInt64(&n.Metrics.RouteFailovers, 1)
+			log.Printf("[ROUTE] Failing over to %s for %s", d.NextHop.Address, target)
 		}
 
-		surrogateCandidates := n.Table.rows[level][surrogateDigit]
-		if len(surrogateCandidates) > 0 {
-			return surrogateCandidates[0], false
+		err = n.sendTo(ctx, d, send, attempt == MAX_ROUTE_ATTEMPTS-1)
+		code := status.Code(err)
+		if (code != codes.Unavailable && code != codes.DeadlineExceeded) || ctx.Err() != nil {
+			return d, err
 		}
+		log.Printf("[ROUTE] Next hop %s failed: %v. Marking suspect.", d.NextHop.Address, err)
+		n.Table.MarkSuspect(d.NextHop.ID)
+	}
+	// Not Unavailable, or the hop before us would blame its own healthy link.
+	return routeDecision{}, status.Errorf(codes.Aborted, "no reachable next hop towards %s: %v", target, err)
+}
+
+func (n *Node) sendTo(ctx context.Context, d routeDecision, send func(context.Context, *TapestryClient, routeDecision) error, last bool) error {
+	client, err := n.getClient(d.NextHop)
+	if err != nil {
+		return status.Error(codes.Unavailable, err.Error())
 	}
+	defer client.Close()
 
-	return Neighbor{ID: n.ID, Address: n.Address}, true
-}
\ No newline at end of file
+	ctx, cancel := n.hopContext(ctx, last)
+	defer cancel()
+	return send(ctx, client, d)
+}
@@ -2,6 +2,7 @@ package node
 
 import (
 	"testing"
+	"time"
 	"tapestry/internal/id"
 )
 
@@ -48,4 +49,171 @@ func TestComputeNextHop_FindsNeighbor(t *testing.T) {
 	if !nextHop.ID.Equals(nbID) {
 		t.Errorf("Should have routed to neighbor")
 	}
-}
\ No newline at end of file
+}
+
+func TestComputeRoute_ReportsSurrogate(t *testing.T) {
+	localID := id.ZeroID
+	n := &Node{
+		ID:      localID,
+		Address: "local",
+		Table:   NewRoutingTable(localID),
+	}
+
+	nbID := id.ZeroID.SetDigit(0, 7)
+	n.Table.Add(Neighbor{ID: nbID, Address: "remote"})
+
+	d := n.computeRoute(id.ZeroID.SetDigit(0, 5))
+	if d.IsRoot || !d.NextHop.ID.Equals(nbID) {
+		t.Fatalf("Should have routed to surrogate neighbor")
+	}
+	if !d.Surrogate {
+		t.Errorf("Route should be flagged as surrogate")
+	}
+	if d.Level != 0 {
+		t.Errorf("Expected level 0, got %d", d.Level)
+	}
+
+	d = n.computeRoute(nbID)
+	if d.Surrogate {
+		t.Errorf("Exact digit match should not be flagged as surrogate")
+	}
+}
+
+func TestComputeNextHop_SurrogateDescendsLevels(t *testing.T) {
+	localID := id.ZeroID
+	n := &Node{
+		ID:      localID,
+		Address: "local",
+		Table:   NewRoutingTable(localID),
+	}
+
+	nbID := id.ZeroID.SetDigit(1, 3)
+	n.Table.Add(Neighbor{ID: nbID, Address: "remote"})
+
+	target := id.ZeroID.SetDigit(0, 5).SetDigit(1, 2)
+
+	nextHop, isRoot := n.computeNextHop(target)
+	if isRoot {
+		t.Fatalf("Node should keep resolving at the next level instead of declaring itself root")
+	}
+	if !nextHop.ID.Equals(nbID) {
+		t.Errorf("Should have routed to the level 1 surrogate")
+	}
+}
+
+func TestComputeRoute_SkipsSuspects(t *testing.T) {
+	localID := id.ZeroID
+	n := &Node{
+		ID:      localID,
+		Address: "local",
+		Table:   NewRoutingTable(localID),
+	}
+
+	primary := id.ZeroID.SetDigit(0, 5)
+	backup := id.ZeroID.SetDigit(0, 5).SetDigit(1, 1)
+	surrogate := id.ZeroID.SetDigit(0, 6)
+	n.Table.Add(Neighbor{ID: primary, Address: "primary", Latency: time.Millisecond})
+	n.Table.Add(Neighbor{ID: backup, Address: "backup", Latency: 2 * time.Millisecond})
+	n.Table.Add(Neighbor{ID: surrogate, Address: "surrogate"})
+
+	target := id.ZeroID.SetDigit(0, 5)
+	if d := n.computeRoute(target); !d.NextHop.ID.Equals(primary) {
+		t.Fatalf("Should have routed to the primary")
+	}
+
+	n.Table.MarkSuspect(primary)
+	if d := n.computeRoute(target); !d.NextHop.ID.Equals(backup) || d.Surrogate {
+		t.Errorf("Should have fallen back to the backup in the same slot")
+	}
+
+	n.Table.MarkSuspect(backup)
+	d := n.computeRoute(target)
+	if !d.NextHop.ID.Equals(surrogate) || !d.Surrogate {
+		t.Errorf("Should have fallen back to the surrogate digit")
+	}
+
+	n.Table.MarkSuspect(surrogate)
+	if _, isRoot := n.computeNextHop(target); !isRoot {
+		t.Errorf("With every candidate suspect the node should be root")
+	}
+
+	if !n.Table.ClearSuspect(primary) {
+		t.Errorf("Primary should have been suspect")
+	}
+	if d := n.computeRoute(target); !d.NextHop.ID.Equals(primary) {
+		t.Errorf("Cleared primary should be used again")
+	}
+	if n.Table.Size() != 3 {
+		t.Errorf("Suspects must stay in the table, got size %d", n.Table.Size())
+	}
+}
+
+func TestSurrogateRouting_UniqueRoot(t *testing.T) {
+	nodes := make(map[id.ID]*Node)
+	var ids []id.ID
+	for i := 0; i < 40; i++ {
+		nid := id.NewRandomID()
+		nodes[nid] = &Node{ID: nid, Address: nid.String(), Table: NewRoutingTable(nid)}
+		ids = append(ids, nid)
+	}
+	for _, a := range ids {
+		for _, b := range ids {
+			nodes[a].Table.Add(Neighbor{ID: b, Address: b.String()})
+		}
+	}
+
+	findRoot := func(start *Node, target id.ID) id.ID {
+		current := start
+		for hops := 0; hops < MAX_HOPS; hops++ {
+			nextHop, isRoot := current.computeNextHop(target)
+			if isRoot {
+				return current.ID
+			}
+			current = nodes[nextHop.ID]
+		}
+		t.Fatalf("Route to %s did not terminate", target)
+		return id.ZeroID
+	}
+
+	for i := 0; i < 200; i++ {
+		target := id.NewRandomID()
+		root := findRoot(nodes[ids[0]], target)
+		for _, nid := range ids[1:] {
+			if other := findRoot(nodes[nid], target); !other.Equals(root) {
+				t.Fatalf("Target %s has two roots: %s and %s", target, root, other)
+			}
+		}
+	}
+}
+
+func TestComputeNextHopExcludingSelf(t *testing.T) {
+	localID := id.ZeroID
+	n := &Node{
+		ID:      localID,
+		Address: "local",
+		Table:   NewRoutingTable(localID),
+	}
+
+	if _, isRoot := n.computeNextHopExcludingSelf(id.ZeroID); !isRoot {
+		t.Errorf("A lone node has nobody to hand off to")
+	}
+
+	farID := id.ZeroID.SetDigit(0, 5)
+	nearID := id.ZeroID.SetDigit(1, 3)
+	n.Table.Add(Neighbor{ID: farID, Address: "far"})
+	n.Table.Add(Neighbor{ID: nearID, Address: "near"})
+
+	if _, isRoot := n.computeNextHop(id.ZeroID); !isRoot {
+		t.Errorf("Node should be root for its own ID")
+	}
+
+	nextHop, isRoot := n.computeNextHopExcludingSelf(id.ZeroID)
+	if isRoot || !nextHop.ID.Equals(nearID) {
+		t.Errorf("Expected handoff to the node sharing the longest prefix, got %s", nextHop.ID)
+	}
+
+	nextHop, isRoot = n.computeNextHopExcludingSelf(id.ZeroID.SetDigit(0, 6))
+	if isRoot || !nextHop.ID.Equals(nearID) {
+		t.Errorf("Expected surrogate through our own digit to resolve to %s, got %s", nearID, nextHop.ID)
+	}
+}
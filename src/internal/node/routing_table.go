@@ -8,17 +8,20 @@ import (
 
 const (
 	K_BACKUPS = 2 
+	K_NEAREST = 8
 )
 
 type RoutingTable struct {
 	localID id.ID
 	rows [id.DIGITS][id.RADIX][]Neighbor
+	suspects map[id.ID]bool // Neighbors a forward failed on; routing skips them
 	lock sync.RWMutex
 }
 
 func NewRoutingTable(localID id.ID) *RoutingTable {
 	return &RoutingTable{
-		localID: localID,
+		localID:  localID,
+		suspects: make(map[id.ID]bool),
 	}
 }
 
@@ -70,6 +73,7 @@ func (rt *RoutingTable) Remove(neighborID id.ID) bool {
 	}
 	digit := neighborID.GetDigit(level)
 
+	delete(rt.suspects, neighborID)
 	list := rt.rows[level][digit]
 	for i, n := range list {
 		if n.ID.Equals(neighborID) {
@@ -80,6 +84,69 @@ func (rt *RoutingTable) Remove(neighborID id.ID) bool {
 	return false
 }
 
+// MarkSuspect keeps neighborID in its slot but has routing skip it. Any
+// failure marks a neighbor suspect: a forward that cannot reach it or times
+// out, its pooled connection failing, or a missed keepalive. The next
+// keepalive settles it, clearing the mark if the neighbor answers and
+// removing it from the table if it does not.
+func (rt *RoutingTable) MarkSuspect(neighborID id.ID) {
+	rt.lock.Lock()
+	defer rt.lock.Unlock()
+	if rt.suspects == nil {
+		rt.suspects = make(map[id.ID]bool)
+	}
+	rt.suspects[neighborID] = true
+}
+
+// ClearSuspect reports whether neighborID was suspect.
+func (rt *RoutingTable) ClearSuspect(neighborID id.ID) bool {
+	rt.lock.Lock()
+	defer rt.lock.Unlock()
+	wasSuspect := rt.suspects[neighborID]
+	delete(rt.suspects, neighborID)
+	return wasSuspect
+}
+
+func (rt *RoutingTable) IsSuspect(neighborID id.ID) bool {
+	rt.lock.RLock()
+	defer rt.lock.RUnlock()
+	return rt.suspects[neighborID]
+}
+
+// firstLive returns the closest neighbor in a slot that is not suspect.
+// Callers must hold the lock.
+func (rt *RoutingTable) firstLive(level, digit int) (Neighbor, bool) {
+	for _, nb := range rt.rows[level][digit] {
+		if !rt.suspects[nb.ID] {
+			return nb, true
+		}
+	}
+	return Neighbor{}, false
+}
+
+// SuspectAddress marks every neighbor reached at address as suspect and
+// returns the ones that were not already.
+func (rt *RoutingTable) SuspectAddress(address string) []Neighbor {
+	rt.lock.Lock()
+	defer rt.lock.Unlock()
+	if rt.suspects == nil {
+		rt.suspects = make(map[id.ID]bool)
+	}
+
+	var marked []Neighbor
+	for i := range rt.rows {
+		for j := range rt.rows[i] {
+			for _, n := range rt.rows[i][j] {
+				if n.Address == address && !rt.suspects[n.ID] {
+					rt.suspects[n.ID] = true
+					marked = append(marked, n)
+				}
+			}
+		}
+	}
+	return marked
+}
+
 func (rt *RoutingTable) Get(level int, digit int) []Neighbor {
 	rt.lock.RLock()
 	defer rt.lock.RUnlock()
@@ -121,6 +188,18 @@ func (rt *RoutingTable) Size() int {
 	return count
 }
 
+// deepestRow returns the last non-empty row, or -1. Callers must hold the lock.
+func (rt *RoutingTable) deepestRow() int {
+	for i := id.DIGITS - 1; i >= 0; i-- {
+		for j := 0; j < id.RADIX; j++ {
+			if len(rt.rows[i][j]) > 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
 func (rt *RoutingTable) sortByProximity(neighbors []Neighbor) {
 	for i := 0; i < len(neighbors); i++ {
 		for j := i + 1; j < len(neighbors); j++ {
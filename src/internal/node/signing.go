@@ -0,0 +1,119 @@
+package node
+
+import (
+	"crypto/ed25519"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	pb "tapestry/api/proto"
+	"tapestry/internal/id"
+)
+
+var (
+	ErrUnsigned      = errors.New("message is not signed")
+	ErrBadSignature  = errors.New("invalid signature")
+	ErrStalePublish  = errors.New("superseded or replayed")
+	ErrFuturePublish = errors.New("timestamp is in the future")
+)
+
+// Domain prefixes keep a signature for one kind of message from being
+// replayed as another.
+const (
+	SIGN_NEIGHBOR  = "tapestry-neighbor:"
+	SIGN_PUBLISH   = "tapestry-publish:"
+	SIGN_UNPUBLISH = "tapestry-unpublish:"
+)
+
+// PUBLISH_MAX_AGE bounds how old a signed (un)publish may be. A live
+// publisher refreshes its pointers well within it, so anything older can
+// only be a replay. Stamps come from the publisher's clock, so both bounds
+// are widened by PUBLISH_CLOCK_SKEW.
+const (
+	PUBLISH_MAX_AGE    = POINTER_TIMEOUT
+	PUBLISH_CLOCK_SKEW = 5 * time.Minute
+)
+
+// KeyID derives a node ID from its public key.
+func KeyID(pub ed25519.PublicKey) id.ID {
+	return id.Hash(string(pub))
+}
+
+func neighborMessage(nodeID id.ID, address string) []byte {
+	return append([]byte(SIGN_NEIGHBOR+address+":"), nodeID.Bytes()...)
+}
+
+func publishMessage(domain string, objID id.ID, publisherID id.ID, stamp int64) []byte {
+	msg := append([]byte(domain), objID.Bytes()...)
+	msg = append(msg, publisherID.Bytes()...)
+	return binary.BigEndian.AppendUint64(msg, uint64(stamp))
+}
+
+// verifyNeighbor checks that the ID is the hash of the advertised key and
+// that the key signed the ID and address.
+func verifyNeighbor(nodeID id.ID, address string, pub, sig []byte) error {
+	if len(pub) == 0 || len(sig) == 0 {
+		return fmt.Errorf("neighbor %s: %w", nodeID, ErrUnsigned)
+	}
+	if len(pub) != ed25519.PublicKeySize {
+		return fmt.Errorf("neighbor %s: public key has %d bytes", nodeID, len(pub))
+	}
+	if !KeyID(pub).Equals(nodeID) {
+		return fmt.Errorf("neighbor %s: ID does not match its public key", nodeID)
+	}
+	if !ed25519.Verify(pub, neighborMessage(nodeID, address), sig) {
+		return fmt.Errorf("neighbor %s: %w", nodeID, ErrBadSignature)
+	}
+	return nil
+}
+
+// verifyPublish checks the publisher's signature on a (un)publish of objID
+// made at stamp, and that it is recent enough to be current. The publisher
+// itself must already have been verified.
+func verifyPublish(domain string, objID id.ID, publisher Neighbor, stamp int64, sig []byte) error {
+	if len(sig) == 0 {
+		return fmt.Errorf("publish of %s by %s: %w", objID, publisher.ID, ErrUnsigned)
+	}
+	if !ed25519.Verify(publisher.PublicKey, publishMessage(domain, objID, publisher.ID, stamp), sig) {
+		return fmt.Errorf("publish of %s by %s: %w", objID, publisher.ID, ErrBadSignature)
+	}
+	age := time.Since(time.Unix(0, stamp))
+	if age > PUBLISH_MAX_AGE+PUBLISH_CLOCK_SKEW {
+		return fmt.Errorf("publish of %s by %s: %w", objID, publisher.ID, ErrStalePublish)
+	}
+	if age < -PUBLISH_CLOCK_SKEW {
+		return fmt.Errorf("publish of %s by %s: %w", objID, publisher.ID, ErrFuturePublish)
+	}
+	return nil
+}
+
+func (n *Node) sign(msg []byte) []byte {
+	if n.key == nil {
+		return nil
+	}
+	return ed25519.Sign(n.key, msg)
+}
+
+// signPublish stamps req with a timestamp later than any this node has used
+// before, so every (un)publish supersedes the ones that came before it.
+func (n *Node) signPublish(req *pb.PublishRequest, domain string) {
+	var objID id.ID
+	copy(objID[:], req.ObjectId.Bytes)
+	req.Timestamp = n.nextPublishStamp()
+	req.Signature = n.sign(publishMessage(domain, objID, n.ID, req.Timestamp))
+}
+
+func (n *Node) nextPublishStamp() int64 {
+	for {
+		last := atomic.LoadInt64(&n.lastPublishStamp)
+		stamp := time.Now().UnixNano()
+		if stamp <= last {
+			stamp = last + 1
+		}
+		if atomic.CompareAndSwapInt64(&n.lastPublishStamp, last, stamp) {
+			return stamp
+		}
+	}
+}
@@ -0,0 +1,150 @@
+package node
+
+import (
+	"context"
+	"crypto/ed25519"
+	"errors"
+	"testing"
+	"time"
+
+	pb "tapestry/api/proto"
+	"tapestry/internal/id"
+)
+
+func newSigningNode(t *testing.T, address string) *Node {
+	pub, key, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &Node{ID: KeyID(pub), key: key, Address: address}
+}
+
+func TestNeighborSignature(t *testing.T) {
+	n := newSigningNode(t, "localhost:1234")
+
+	nb, err := NeighborFromProto(n.toProtoNeighbor())
+	if err != nil {
+		t.Fatalf("Valid advertisement rejected: %v", err)
+	}
+	if _, err := NeighborFromProto(nb.ToProto()); err != nil {
+		t.Errorf("Forwarded advertisement rejected: %v", err)
+	}
+
+	moved := n.toProtoNeighbor()
+	moved.Address = "localhost:6666"
+	if _, err := NeighborFromProto(moved); err == nil {
+		t.Errorf("Advertisement with a changed address was accepted")
+	}
+
+	claimed := n.toProtoNeighbor()
+	claimed.Id = &pb.NodeID{Bytes: id.NewRandomID().Bytes()}
+	if _, err := NeighborFromProto(claimed); err == nil {
+		t.Errorf("Advertisement for an ID not derived from the key was accepted")
+	}
+
+	unsigned := &pb.Neighbor{Id: &pb.NodeID{Bytes: n.ID.Bytes()}, Address: n.Address}
+	if _, err := NeighborFromProto(unsigned); err == nil {
+		t.Errorf("Unsigned advertisement was accepted")
+	}
+}
+
+func TestPublishSignature(t *testing.T) {
+	n := newSigningNode(t, "localhost:1234")
+	publisher, _ := NeighborFromProto(n.toProtoNeighbor())
+	objID := id.Hash("k-0")
+
+	req := &pb.PublishRequest{ObjectId: &pb.NodeID{Bytes: objID.Bytes()}}
+	n.signPublish(req, SIGN_PUBLISH)
+
+	if err := verifyPublish(SIGN_PUBLISH, objID, publisher, req.Timestamp, req.Signature); err != nil {
+		t.Errorf("Valid publish rejected: %v", err)
+	}
+	if err := verifyPublish(SIGN_UNPUBLISH, objID, publisher, req.Timestamp, req.Signature); err == nil {
+		t.Errorf("Publish signature was accepted for an unpublish")
+	}
+	if err := verifyPublish(SIGN_PUBLISH, id.Hash("other-0"), publisher, req.Timestamp, req.Signature); err == nil {
+		t.Errorf("Publish signature was accepted for another object")
+	}
+	if err := verifyPublish(SIGN_PUBLISH, objID, publisher, req.Timestamp+1, req.Signature); err == nil {
+		t.Errorf("Publish signature was accepted for another timestamp")
+	}
+
+	other := newSigningNode(t, "localhost:4321")
+	forger, _ := NeighborFromProto(other.toProtoNeighbor())
+	if err := verifyPublish(SIGN_PUBLISH, objID, forger, req.Timestamp, req.Signature); err == nil {
+		t.Errorf("Publish signature was accepted for another publisher")
+	}
+}
+
+func TestPublishReplay(t *testing.T) {
+	n := newSigningNode(t, "localhost:1234")
+	n.LocationPointers = make(map[id.ID][]*PointerEntry)
+	n.withdrawn = make(map[id.ID]map[string]int64)
+	publisher, _ := NeighborFromProto(n.toProtoNeighbor())
+	objID := id.Hash("k-0")
+
+	old := &pb.PublishRequest{ObjectId: &pb.NodeID{Bytes: objID.Bytes()}}
+	n.signPublish(old, SIGN_PUBLISH)
+	stale := time.Now().Add(-2 * (PUBLISH_MAX_AGE + PUBLISH_CLOCK_SKEW)).UnixNano()
+	staleSig := n.sign(publishMessage(SIGN_PUBLISH, objID, n.ID, stale))
+	if err := verifyPublish(SIGN_PUBLISH, objID, publisher, stale, staleSig); !errors.Is(err, ErrStalePublish) {
+		t.Errorf("Expected an expired publish to be stale, got %v", err)
+	}
+
+	// A publisher whose clock is off by less than the margin either way is
+	// still heard; one further ahead is not.
+	for _, skew := range []time.Duration{-PUBLISH_MAX_AGE - PUBLISH_CLOCK_SKEW/2, PUBLISH_CLOCK_SKEW / 2} {
+		stamp := time.Now().Add(skew).UnixNano()
+		sig := n.sign(publishMessage(SIGN_PUBLISH, objID, n.ID, stamp))
+		if err := verifyPublish(SIGN_PUBLISH, objID, publisher, stamp, sig); err != nil {
+			t.Errorf("Publish stamped %v off was rejected: %v", skew, err)
+		}
+	}
+	future := time.Now().Add(2 * PUBLISH_CLOCK_SKEW).UnixNano()
+	futureSig := n.sign(publishMessage(SIGN_PUBLISH, objID, n.ID, future))
+	if err := verifyPublish(SIGN_PUBLISH, objID, publisher, future, futureSig); !errors.Is(err, ErrFuturePublish) {
+		t.Errorf("Expected a publish from the future to be refused, got %v", err)
+	}
+
+	unpub := &pb.PublishRequest{ObjectId: &pb.NodeID{Bytes: objID.Bytes()}}
+	n.signPublish(unpub, SIGN_UNPUBLISH)
+	if unpub.Timestamp <= old.Timestamp {
+		t.Fatalf("Timestamps are not increasing: %d after %d", unpub.Timestamp, old.Timestamp)
+	}
+
+	if !n.addLocationPointer(objID, publisher, old.Signature, old.Timestamp) {
+		t.Fatalf("Publish rejected")
+	}
+	if !n.removeLocationPointer(objID, publisher.ID, unpub.Timestamp) {
+		t.Fatalf("Unpublish rejected")
+	}
+	if n.addLocationPointer(objID, publisher, old.Signature, old.Timestamp) {
+		t.Errorf("Publish replayed after its unpublish was accepted")
+	}
+
+	republish := &pb.PublishRequest{ObjectId: &pb.NodeID{Bytes: objID.Bytes()}}
+	n.signPublish(republish, SIGN_PUBLISH)
+	if !n.addLocationPointer(objID, publisher, republish.Signature, republish.Timestamp) {
+		t.Fatalf("Re-publish rejected")
+	}
+	if n.removeLocationPointer(objID, publisher.ID, unpub.Timestamp) {
+		t.Errorf("Unpublish replayed after a re-publish was accepted")
+	}
+	if len(n.getLocationPointers(objID)) != 1 {
+		t.Errorf("Replayed unpublish removed the pointer")
+	}
+}
+
+func TestPublishRejectsMalformedObjectID(t *testing.T) {
+	n := newSigningNode(t, "localhost:1234")
+
+	for _, objID := range []*pb.NodeID{nil, {Bytes: []byte{1, 2, 3}}} {
+		req := &pb.PublishRequest{ObjectId: objID, Publisher: n.toProtoNeighbor()}
+		if _, err := n.Publish(context.Background(), req); err == nil {
+			t.Errorf("Publish with object ID %v was accepted", objID)
+		}
+		if _, err := n.Unpublish(context.Background(), req); err == nil {
+			t.Errorf("Unpublish with object ID %v was accepted", objID)
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package node
+
+import (
+	"sync"
+
+	"tapestry/internal/id"
+)
+
+// Store holds the objects a node serves. Implementations must be safe for
+// concurrent use.
+type Store interface {
+	Get(objID id.ID) (Object, bool)
+	Put(objID id.ID, obj Object) error
+	Delete(objID id.ID) error
+	List() []Object
+	Len() int
+	Close() error
+}
+
+type MemoryStore struct {
+	objects map[id.ID]Object
+	lock    sync.RWMutex
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{objects: make(map[id.ID]Object)}
+}
+
+func (s *MemoryStore) Get(objID id.ID) (Object, bool) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	obj, ok := s.objects[objID]
+	return obj, ok
+}
+
+func (s *MemoryStore) Put(objID id.ID, obj Object) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.objects[objID] = obj
+	return nil
+}
+
+func (s *MemoryStore) Delete(objID id.ID) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	delete(s.objects, objID)
+	return nil
+}
+
+func (s *MemoryStore) List() []Object {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	result := make([]Object, 0, len(s.objects))
+	for _, obj := range s.objects {
+		result = append(result, obj)
+	}
+	return result
+}
+
+func (s *MemoryStore) Len() int {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return len(s.objects)
+}
+
+func (s *MemoryStore) Close() error {
+	return nil
+}
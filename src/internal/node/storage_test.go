@@ -0,0 +1,142 @@
+package node
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"tapestry/internal/id"
+	"testing"
+)
+
+func testStoreBasics(t *testing.T, s Store) {
+	obj := Object{Key: "alpha", Data: []byte("one")}
+	objID := id.Hash(obj.Key)
+
+	if err := s.Put(objID, obj); err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+	got, ok := s.Get(objID)
+	if !ok || string(got.Data) != "one" {
+		t.Errorf("Expected 'one', got %q (found=%v)", got.Data, ok)
+	}
+
+	s.Put(objID, Object{Key: "alpha", Data: []byte("two")})
+	if got, _ := s.Get(objID); string(got.Data) != "two" {
+		t.Errorf("Overwrite not applied, got %q", got.Data)
+	}
+	if s.Len() != 1 || len(s.List()) != 1 {
+		t.Errorf("Expected exactly 1 object, got %d", s.Len())
+	}
+
+	if err := s.Delete(objID); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if _, ok := s.Get(objID); ok {
+		t.Errorf("Object still present after delete")
+	}
+}
+
+func TestMemoryStore(t *testing.T) {
+	testStoreBasics(t, NewMemoryStore())
+}
+
+func TestLogStore(t *testing.T) {
+	s, err := OpenLogStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	defer s.Close()
+	testStoreBasics(t, s)
+}
+
+func TestLogStoreReopen(t *testing.T) {
+	dir := t.TempDir()
+	s, err := OpenLogStore(dir)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	s.Put(id.Hash("kept"), Object{Key: "kept", Data: []byte("v1")})
+	s.Put(id.Hash("kept"), Object{Key: "kept", Data: []byte("v2")})
+	s.Put(id.Hash("dropped"), Object{Key: "dropped", Data: []byte("x")})
+	s.Delete(id.Hash("dropped"))
+	s.Close()
+
+	f, _ := os.OpenFile(filepath.Join(dir, LOG_FILE_NAME), os.O_APPEND|os.O_WRONLY, 0644)
+	f.WriteString(`{"op":"put","id":"abc`)
+	f.Close()
+
+	s, err = OpenLogStore(dir)
+	if err != nil {
+		t.Fatalf("Reopen failed: %v", err)
+	}
+	defer s.Close()
+
+	if s.Len() != 1 {
+		t.Errorf("Expected 1 object after reload, got %d", s.Len())
+	}
+	if got, ok := s.Get(id.Hash("kept")); !ok || string(got.Data) != "v2" {
+		t.Errorf("Expected latest value v2, got %q", got.Data)
+	}
+}
+
+func TestLogStoreAppendAfterTornTail(t *testing.T) {
+	dir := t.TempDir()
+	s, err := OpenLogStore(dir)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	s.Put(id.Hash("before"), Object{Key: "before", Data: []byte("v1")})
+	s.Close()
+
+	f, _ := os.OpenFile(filepath.Join(dir, LOG_FILE_NAME), os.O_APPEND|os.O_WRONLY, 0644)
+	f.WriteString(`{"op":"put","id":"abc`)
+	f.Close()
+
+	s, err = OpenLogStore(dir)
+	if err != nil {
+		t.Fatalf("Reopen failed: %v", err)
+	}
+	s.Put(id.Hash("after"), Object{Key: "after", Data: []byte("v2")})
+	s.Close()
+
+	s, err = OpenLogStore(dir)
+	if err != nil {
+		t.Fatalf("Second reopen failed: %v", err)
+	}
+	defer s.Close()
+
+	if got, ok := s.Get(id.Hash("after")); !ok || string(got.Data) != "v2" {
+		t.Errorf("Record written after the torn tail was lost, got %q (found=%v)", got.Data, ok)
+	}
+	if _, ok := s.Get(id.Hash("before")); !ok {
+		t.Errorf("Record written before the torn tail was lost")
+	}
+}
+
+func TestLogStoreCompaction(t *testing.T) {
+	dir := t.TempDir()
+	s, err := OpenLogStore(dir)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+
+	objID := id.Hash("hot")
+	for i := 0; i <= COMPACT_MIN_RECORDS; i++ {
+		s.Put(objID, Object{Key: "hot", Data: []byte(fmt.Sprintf("v%d", i))})
+	}
+	s.Close()
+
+	info, err := os.Stat(filepath.Join(dir, LOG_FILE_NAME))
+	if err != nil {
+		t.Fatalf("Stat failed: %v", err)
+	}
+	if info.Size() > 1024 {
+		t.Errorf("Log was not compacted, size %d bytes", info.Size())
+	}
+
+	s, _ = OpenLogStore(dir)
+	defer s.Close()
+	if got, _ := s.Get(objID); string(got.Data) != fmt.Sprintf("v%d", COMPACT_MIN_RECORDS) {
+		t.Errorf("Compaction lost the latest value, got %q", got.Data)
+	}
+}
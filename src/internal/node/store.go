@@ -4,6 +4,9 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"sync"
+	"time"
+
 	pb "tapestry/api/proto"
 	"tapestry/internal/id"
 )
@@ -14,80 +17,298 @@ const (
 )
 
 type Object struct {
-	Key  string
-	Data string
+	Key    string
+	Data   []byte
+	Chunks []string `json:",omitempty"` // Block keys of a chunked object, in order
+	Size   int64
+	Version  VectorClock
+	Siblings []Object `json:",omitempty"` // Concurrent values that conflict with this one
+	Owner    string   `json:",omitempty"` // Node that tracks and repairs the replicas
+	Deleted   bool `json:",omitempty"` // Tombstone: the key was removed at DeletedAt
+	DeletedAt time.Time
+	ExpiresAt time.Time // Zero never expires
+	Refs      map[string]time.Time `json:",omitempty"` // Blocks only: manifest key -> when it last listed the block
 }
 
-func (n *Node) StoreAndPublish(key string, data string) error {
-	n.storeLocal(key, data)
+func (n *Node) StoreAndPublish(key string, data []byte) error {
+	return n.StoreAndPublishWithOptions(context.Background(), key, data, WriteOptions{})
+}
 
-	go n.publishSelfSalted(key)
+// StoreAndPublishWithOptions blocks until opts.W copies, counting the local
+// one, have been stored. The remaining replicas are still written in the
+// background. Cancelling ctx before the quorum is reached abandons every
+// replica write still in flight. A write that misses its quorum may still be
+// partially applied: the copies already stored stay, and a later write to
+// the key supersedes them.
+func (n *Node) StoreAndPublishWithOptions(ctx context.Context, key string, data []byte, opts WriteOptions) error {
+	w := opts.W
+	if w <= 0 {
+		w = n.WriteQuorum
+	}
+	if w > REPLICATION_FACTOR {
+		return fmt.Errorf("write quorum %d exceeds replication factor %d", w, REPLICATION_FACTOR)
+	}
 
-	backups := n.SelectRandomNeighbors(REPLICATION_FACTOR - 1)
-	
-	if len(backups) == 0 {
-		log.Printf("[CRITICAL] No neighbors found for replication of '%s'. Data is NOT fault tolerant.", key)
-	} else if len(backups) < REPLICATION_FACTOR-1 {
-		log.Printf("[WARNING] Only found %d/%d backups for '%s'.", len(backups), REPLICATION_FACTOR-1, key)
+	var expiresAt time.Time
+	if opts.TTL > 0 {
+		expiresAt = time.Now().Add(opts.TTL)
+	}
+
+	ctx, cancel := withBudget(ctx, n.Timeouts.Put)
+	defer cancel()
+
+	if len(data) > CHUNK_SIZE {
+		return n.storeChunked(ctx, key, data, expiresAt, w)
+	}
+	return n.storeAndReplicate(ctx, Object{Key: key, Data: data, Size: int64(len(data)), ExpiresAt: expiresAt}, w)
+}
+
+// storeAndReplicate writes obj to its placement set. The writer keeps and
+// counts a copy only if it is a member of that set; otherwise the first
+// member owns the object.
+func (n *Node) storeAndReplicate(ctx context.Context, obj Object, w int) error {
+	key := obj.Key
+	replicas := n.ReplicaSet(key)
+
+	local := false
+	var backups []Neighbor
+	for _, replica := range replicas {
+		if replica.ID.Equals(n.ID) {
+			local = true
+		} else {
+			backups = append(backups, replica)
+		}
+	}
+
+	var err error
+	stored := 0
+	if local {
+		obj, err = n.storeNewVersion(obj, nil)
+		if err != nil {
+			return err
+		}
+		stored = 1
+	} else if len(backups) > 0 {
+		obj = n.remoteNewVersion(ctx, obj, backups[0])
+	}
+
+	if len(replicas) == 0 {
+		log.Printf("[CRITICAL] No replicas found for '%s'. Data is NOT stored.", key)
+	} else if len(replicas) < REPLICATION_FACTOR {
+		log.Printf("[WARNING] Only found %d/%d replicas for '%s'.", len(replicas), REPLICATION_FACTOR, key)
 	}
 	
+	// Replica writes and publishes follow ctx only until the quorum is in;
+	// the stragglers then finish on their own budgets after we return. A
+	// write abandoned with the caller is not hinted, since the caller was
+	// told it failed.
+	fanout, cancelFanout := context.WithCancel(context.WithoutCancel(ctx))
+	detach := context.AfterFunc(ctx, cancelFanout)
+	var wg sync.WaitGroup
+	defer func() {
+		go func() {
+			wg.Wait()
+			detach()
+			cancelFanout()
+		}()
+	}()
+
+	if local {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			n.publishSelfSalted(fanout, key)
+		}()
+	}
+
+	acks := make(chan bool, len(backups))
 	for _, backup := range backups {
+		wg.Add(1)
 		go func(target Neighbor) {
-			client, err := GetClient(target.Address)
+			defer wg.Done()
+			client, err := n.getClient(target)
 			if err != nil {
 				log.Printf("Failed to connect to replica %s: %v", target.Address, err)
+				if fanout.Err() == nil {
+					n.queueHint(target, obj)
+				}
+				acks <- false
 				return
 			}
 			defer client.Close()
-			_, err = client.Replicate(context.Background(), &pb.ReplicateRequest{Key: key, Data: data})
+			rctx, cancel := n.rpcContext(fanout)
+			defer cancel()
+			_, err = client.Replicate(rctx, obj.toReplicateRequest())
 			if err == nil {
+				if obj.Owner == n.ID.String() {
+					n.trackHolder(key, target)
+				}
 				log.Printf("Replicated '%s' to %s", key, target.Address)
 			} else {
 				log.Printf("Failed to replicate '%s' to %s: %v", key, target.Address, err)
+				if fanout.Err() == nil {
+					n.queueHint(target, obj)
+				}
 			}
+			acks <- err == nil
 		}(backup)
 	}
 
+	for i := 0; i < len(backups) && stored < w; i++ {
+		select {
+		case ok := <-acks:
+			if ok {
+				stored++
+			}
+		case <-ctx.Done():
+			return fmt.Errorf("write quorum not reached for '%s': %d/%d copies stored: %w", key, stored, w, ctx.Err())
+		}
+	}
+	if stored < w {
+		return fmt.Errorf("write quorum not reached for '%s': %d/%d copies stored", key, stored, w)
+	}
+	detach()
 	return nil
 }
 
 func (n *Node) Replicate(ctx context.Context, req *pb.ReplicateRequest) (*pb.Ack, error) {
 	log.Printf("Node %s received Replica for '%s'", n.ID, req.Key)
-	n.storeLocal(req.Key, req.Data)
-	go n.publishSelfSalted(req.Key)
+	obj := objectFromProto(req)
+	if obj.Expired() {
+		return &pb.Ack{Success: true}, nil
+	}
+	if err := n.storeLocal(obj); err != nil {
+		return &pb.Ack{Success: false}, err
+	}
+	go n.publishSelfSalted(context.Background(), req.Key)
 	return &pb.Ack{Success: true}, nil
 }
 
-func (n *Node) publishSelfSalted(key string) {
+// publishSelfSalted advertises this node's copy of key under each salted ID
+// and returns once every publish has finished or ctx is done.
+func (n *Node) publishSelfSalted(ctx context.Context, key string) {
+	var wg sync.WaitGroup
 	for i := 0; i < SALT_COUNT; i++ {
 		saltedKey := fmt.Sprintf("%s-%d", key, i)
 		targetID := id.Hash(saltedKey)
 
 		req := &pb.PublishRequest{
-			ObjectId: &pb.NodeID{Bytes: targetID.Bytes()},
-			Publisher: &pb.Neighbor{
-				Id:      &pb.NodeID{Bytes: n.ID.Bytes()},
-				Address: n.Address,
-			},
-			HopLimit: 20,
+			ObjectId:  &pb.NodeID{Bytes: targetID.Bytes()},
+			Publisher: n.toProtoNeighbor(),
+			HopLimit:  20,
 		}
+		n.signPublish(req, SIGN_PUBLISH)
 		
-		go func(r *pb.PublishRequest, idx int) {
-			n.Publish(context.Background(), r)
-		}(req, i)
+		wg.Add(1)
+		go func(r *pb.PublishRequest) {
+			defer wg.Done()
+			pctx, cancel := n.routeContext(ctx)
+			defer cancel()
+			n.Publish(pctx, r)
+		}(req)
 	}
+	wg.Wait()
 }
 
 func (n *Node) Get(key string) (Object, error) {
+	return n.GetWithOptions(context.Background(), key, ReadOptions{})
+}
+
+// GetWithOptions retrieves the newest version of the object stored under key
+// among opts.R copies, reassembling it from its blocks if it was chunked.
+// Conflicting concurrent values are returned as Siblings. Every lookup and
+// fetch stops as soon as ctx is done.
+func (n *Node) GetWithOptions(ctx context.Context, key string, opts ReadOptions) (Object, error) {
+	r := opts.R
+	if r <= 0 {
+		r = n.ReadQuorum
+	}
+
+	ctx, cancel := withBudget(ctx, n.Timeouts.Get)
+	defer cancel()
+
+	obj, err := n.getObject(ctx, key, r, opts.ReadRepair || n.ReadRepair)
+	if err != nil {
+		return Object{}, err
+	}
+	obj, ok := visible(obj)
+	if !ok {
+		return Object{}, fmt.Errorf("object '%s' was deleted", key)
+	}
+	if len(obj.Chunks) > 0 {
+		obj, err = n.assemble(ctx, obj)
+		if err != nil {
+			return Object{}, err
+		}
+	}
+	for i, sib := range obj.Siblings {
+		if len(sib.Chunks) > 0 {
+			if full, err := n.assemble(ctx, sib); err == nil {
+				obj.Siblings[i] = full
+			}
+		}
+	}
+	return obj, nil
+}
+
+// getObject merges the copies held by up to r publishers, the local store
+// included. r <= 0 consults every publisher that can be found, as does
+// repair, which then brings lagging copies up to date in the background.
+func (n *Node) getObject(ctx context.Context, key string, r int, repair bool) (Object, error) {
 	objID := id.Hash(key)
-	n.objLock.RLock()
-	if obj, ok := n.LocalObjects[objID]; ok {
-		n.objLock.RUnlock()
-		return obj, nil
+	result, found := n.Objects.Get(objID)
+	if found && result.Expired() {
+		result, found = Object{}, false
+	}
+	answered := 0
+	var copies []publisherCopy
+	if found {
+		answered++
+		copies = append(copies, publisherCopy{holder: Neighbor{ID: n.ID, Address: n.Address}, obj: result, found: true})
+	}
+	asked := map[string]bool{n.ID.String(): true}
+	enough := func() bool {
+		return !repair && r > 0 && answered >= r
 	}
-	n.objLock.RUnlock()
 
-	for i := 0; i < SALT_COUNT; i++ {
+	fetchFrom := func(publishers []Neighbor) {
+		for _, pub := range publishers {
+			if enough() || ctx.Err() != nil {
+				return
+			}
+			if asked[pub.ID.String()] {
+				continue
+			}
+			asked[pub.ID.String()] = true
+
+			client, err := n.getClient(pub)
+			if err != nil {
+				continue
+			}
+			
+			fctx, cancel := n.rpcContext(ctx)
+			obj, ok, err := fetchStream(fctx, client, key)
+			cancel()
+			client.Close()
+			
+			if err != nil {
+				continue
+			}
+			copies = append(copies, publisherCopy{holder: pub, obj: obj, found: ok})
+			if !ok {
+				continue
+			}
+			answered++
+			if found {
+				result = resolve(result, obj)
+			} else {
+				result = obj
+				found = true
+			}
+		}
+	}
+
+	for i := 0; i < SALT_COUNT && !enough() && ctx.Err() == nil; i++ {
 		saltedKey := fmt.Sprintf("%s-%d", key, i)
 		targetID := id.Hash(saltedKey)
 
@@ -98,52 +319,290 @@ func (n *Node) Get(key string) (Object, error) {
 			HopLimit: 20,
 		}
 		
-		resp, err := n.Lookup(context.Background(), lookupReq)
+		lctx, cancel := n.routeContext(ctx)
+		resp, err := n.Lookup(lctx, lookupReq)
+		cancel()
 		if err != nil || !resp.Found || len(resp.Publishers) == 0 {
 			continue 
 		}
 
+		var publishers []Neighbor
 		for _, pubProto := range resp.Publishers {
-			pubAddr := pubProto.Address
-			client, err := GetClient(pubAddr)
-			if err != nil {
-				continue
-			}
-			
-			fetchResp, err := client.Fetch(context.Background(), &pb.FetchRequest{Key: key})
-			client.Close()
-			
-			if err == nil && fetchResp.Found {
-				return Object{Key: key, Data: fetchResp.Data}, nil
+			if pub, err := NeighborFromProto(pubProto); err == nil {
+				publishers = append(publishers, pub)
 			}
 		}
+		fetchFrom(publishers)
+	}
+
+	// A pointer met on the way only lists the publishers whose routes crossed
+	// that node, so ask the roots for the rest.
+	if !enough() && ctx.Err() == nil {
+		fetchFrom(n.findPublishers(ctx, key))
 	}
 
-	return Object{}, fmt.Errorf("object not found after checking %d paths", SALT_COUNT)
+	if err := ctx.Err(); err != nil && (!found || answered < r) {
+		return Object{}, fmt.Errorf("get '%s' gave up after %d copies: %w", key, answered, err)
+	}
+	if !found {
+		return Object{}, fmt.Errorf("object not found after checking %d paths", SALT_COUNT)
+	}
+	if answered < r {
+		return Object{}, fmt.Errorf("read quorum not reached for '%s': %d/%d copies answered", key, answered, r)
+	}
+	if repair {
+		go n.readRepair(result, copies)
+	}
+	return result, nil
 }
 
-func (n *Node) storeLocal(key, data string) {
-	objID := id.Hash(key)
-	n.objLock.Lock()
-	n.LocalObjects[objID] = Object{Key: key, Data: data}
-	n.objLock.Unlock()
+// storeLocal merges obj into the local copy so that a stale replica never
+// overwrites a newer value.
+func (n *Node) storeLocal(obj Object) error {
+	n.storeLock.Lock()
+	defer n.storeLock.Unlock()
+
+	objID := id.Hash(obj.Key)
+	if existing, ok := n.Objects.Get(objID); ok {
+		merged := resolve(existing, obj)
+		if obj.Owner != "" && merged.Version.Compare(obj.Version) == EQUAL {
+			merged.Owner = obj.Owner
+		}
+		obj = merged
+	}
+	return n.Objects.Put(objID, obj)
+}
+
+// remoteNewVersion versions obj for a writer outside the placement set. The
+// clock is taken from the current copy, so the write supersedes it even
+// though this node keeps none; owner becomes the object's owner. Two writes
+// made before any copy is readable get the same clock and end up siblings.
+func (n *Node) remoteNewVersion(ctx context.Context, obj Object, owner Neighbor) Object {
+	obj.Version = n.currentClock(ctx, obj.Key).Increment(n.ID.String())
+	obj.Siblings = nil
+	obj.Owner = owner.ID.String()
+	return obj
+}
+
+// currentClock returns the clock of the nearest copy of key, or an empty
+// clock if there is none.
+func (n *Node) currentClock(ctx context.Context, key string) VectorClock {
+	if existing, err := n.getObject(ctx, key, 1, false); err == nil {
+		return existing.clock()
+	}
+	return VectorClock{}
+}
+
+// storeNewVersion stores obj as a local write that supersedes every version
+// this node has seen, including any siblings, and the clock seen.
+func (n *Node) storeNewVersion(obj Object, seen VectorClock) (Object, error) {
+	n.storeLock.Lock()
+	defer n.storeLock.Unlock()
+
+	objID := id.Hash(obj.Key)
+	clock := seen.Copy()
+	if existing, ok := n.Objects.Get(objID); ok {
+		clock = clock.Merge(existing.clock())
+		obj.Refs = mergeRefs(existing.Refs, obj.Refs)
+	}
+	obj.Version = clock.Increment(n.ID.String())
+	obj.Siblings = nil
+	obj.Owner = n.ID.String()
+	return obj, n.Objects.Put(objID, obj)
+}
+
+func objectFromProto(req *pb.ReplicateRequest) Object {
+	return Object{
+		Key:      req.Key,
+		Data:     req.Data,
+		Chunks:   req.Chunks,
+		Size:     req.Size,
+		Version:  versionFromProto(req.Version),
+		Siblings: siblingsFromProto(req.Key, req.Siblings),
+		Owner:    req.Owner,
+		Deleted:   req.Deleted,
+		DeletedAt: unixNanos(req.DeletedAt),
+		ExpiresAt: unixNanos(req.ExpiresAt),
+		Refs:      refsFromProto(req.Refs),
+	}
 }
 
+func (o Object) toReplicateRequest() *pb.ReplicateRequest {
+	return &pb.ReplicateRequest{
+		Key:      o.Key,
+		Data:     o.Data,
+		Chunks:   o.Chunks,
+		Size:     o.Size,
+		Version:  o.Version.toProto(),
+		Siblings: siblingsToProto(o.Siblings),
+		Owner:    o.Owner,
+		Deleted:   o.Deleted,
+		DeletedAt: toUnixNanos(o.DeletedAt),
+		ExpiresAt: toUnixNanos(o.ExpiresAt),
+		Refs:      refsToProto(o.Refs),
+	}
+}
+
+// Remove deletes key everywhere by writing a tombstone that supersedes every
+// version this node has seen. The tombstone is replicated like a write to
+// the placement set and to every node currently publishing the key. A
+// chunked object's blocks are left to CollectBlocks, since other manifests
+// may share them.
 func (n *Node) Remove(key string) {
+	n.remove(context.Background(), key)
+}
+
+func (n *Node) remove(ctx context.Context, key string) {
+	ctx, cancel := withBudget(ctx, n.Timeouts.Put)
+	defer cancel()
+
+	n.tombstone(ctx, key)
+}
+
+func (n *Node) tombstone(ctx context.Context, key string) {
+	holders := n.findPublishers(ctx, key)
+
+	// The copies may all be elsewhere if we are outside the placement set.
+	seen := n.currentClock(ctx, key)
+	tomb, err := n.storeNewVersion(Object{Key: key, Deleted: true, DeletedAt: time.Now()}, seen)
+	if err != nil {
+		log.Printf("[STORE] Failed to store tombstone for '%s': %v", key, err)
+		return
+	}
+
+	targets := make(map[string]Neighbor)
+	for _, nb := range append(holders, n.ReplicaSet(key)...) {
+		if !nb.ID.Equals(n.ID) {
+			targets[nb.ID.String()] = nb
+		}
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		n.publishSelfSalted(ctx, key)
+	}()
+	for _, target := range targets {
+		wg.Add(1)
+		go func(target Neighbor) {
+			defer wg.Done()
+			client, err := n.getClient(target)
+			if err != nil {
+				log.Printf("Failed to connect to replica %s: %v", target.Address, err)
+				n.queueHint(target, tomb)
+				return
+			}
+			defer client.Close()
+			rctx, cancel := n.rpcContext(ctx)
+			defer cancel()
+			_, err = client.Replicate(rctx, tomb.toReplicateRequest())
+			if err != nil {
+				log.Printf("Failed to send tombstone for '%s' to %s: %v", key, target.Address, err)
+				n.queueHint(target, tomb)
+				return
+			}
+			if tomb.Owner == n.ID.String() {
+				n.trackHolder(key, target)
+			}
+		}(target)
+	}
+	wg.Wait()
+}
+
+func (n *Node) removeLocal(ctx context.Context, key string) {
 	objID := id.Hash(key)
-	n.objLock.Lock()
-	delete(n.LocalObjects, objID)
-	n.objLock.Unlock()
+	if err := n.Objects.Delete(objID); err != nil {
+		log.Printf("[STORE] Failed to delete '%s': %v", key, err)
+	}
+	n.forgetHolders(key)
+
+	n.unpublishSelfSalted(ctx, key)
+}
+
+func (n *Node) unpublishSelfSalted(ctx context.Context, key string) {
+	var wg sync.WaitGroup
+	for i := 0; i < SALT_COUNT; i++ {
+		saltedKey := fmt.Sprintf("%s-%d", key, i)
+		targetID := id.Hash(saltedKey)
+
+		req := &pb.PublishRequest{
+			ObjectId:  &pb.NodeID{Bytes: targetID.Bytes()},
+			Publisher: n.toProtoNeighbor(),
+			HopLimit:  MAX_HOPS,
+		}
+		n.signPublish(req, SIGN_UNPUBLISH)
+
+		wg.Add(1)
+		go func(r *pb.PublishRequest) {
+			defer wg.Done()
+			uctx, cancel := n.routeContext(ctx)
+			defer cancel()
+			n.Unpublish(uctx, r)
+		}(req)
+	}
+	wg.Wait()
+}
+
+// findPublishers collects every node that currently advertises a copy of key.
+// Path nodes only know the publishers whose route crossed them, so the
+// lookup is sent straight to the root of each salted ID.
+func (n *Node) findPublishers(ctx context.Context, key string) []Neighbor {
+	seen := make(map[string]bool)
+	var publishers []Neighbor
+
+	for i := 0; i < SALT_COUNT && ctx.Err() == nil; i++ {
+		saltedKey := fmt.Sprintf("%s-%d", key, i)
+		targetID := id.Hash(saltedKey)
+
+		root, err := n.findRoot(ctx, targetID)
+		if err != nil {
+			continue
+		}
+		client, err := n.getClient(root)
+		if err != nil {
+			continue
+		}
+		lctx, cancel := n.rpcContext(ctx)
+		resp, err := client.Lookup(lctx, &pb.LookupRequest{
+			ObjectId: &pb.NodeID{Bytes: targetID.Bytes()},
+			HopLimit: MAX_HOPS,
+		})
+		cancel()
+		client.Close()
+		if err != nil || !resp.Found {
+			continue
+		}
+		for _, pubProto := range resp.Publishers {
+			pub, err := NeighborFromProto(pubProto)
+			if err != nil || seen[pub.ID.String()] {
+				continue
+			}
+			seen[pub.ID.String()] = true
+			publishers = append(publishers, pub)
+		}
+	}
+	return publishers
 }
 
 func (n *Node) Fetch(ctx context.Context, req *pb.FetchRequest) (*pb.FetchResponse, error) {
 	objID := id.Hash(req.Key)
-	n.objLock.RLock()
-	obj, ok := n.LocalObjects[objID]
-	n.objLock.RUnlock()
+	obj, ok := n.Objects.Get(objID)
 
-	if !ok {
+	if !ok || obj.Expired() {
 		return &pb.FetchResponse{Found: false}, nil
 	}
-	return &pb.FetchResponse{Data: obj.Data, Found: true}, nil
+	return &pb.FetchResponse{
+		Data:     obj.Data,
+		Found:    true,
+		Chunks:   obj.Chunks,
+		Size:     obj.Size,
+		Version:  obj.Version.toProto(),
+		Siblings: siblingsToProto(obj.Siblings),
+		Owner:    obj.Owner,
+		Deleted:   obj.Deleted,
+		DeletedAt: toUnixNanos(obj.DeletedAt),
+		ExpiresAt: toUnixNanos(obj.ExpiresAt),
+		Refs:      refsToProto(obj.Refs),
+	}, nil
 }
\ No newline at end of file
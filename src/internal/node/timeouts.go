@@ -0,0 +1,72 @@
+package node
+
+import (
+	"context"
+	"time"
+)
+
+const (
+	DEFAULT_RPC_TIMEOUT   = 5 * time.Second  // One call to one peer
+	DEFAULT_ROUTE_TIMEOUT = 10 * time.Second // A message routed hop by hop to a root
+	DEFAULT_GET_TIMEOUT   = 30 * time.Second
+	DEFAULT_PUT_TIMEOUT   = 30 * time.Second
+)
+
+// Timeouts bound each kind of operation. A caller's own deadline still wins
+// when it is earlier, and gRPC carries whatever budget is left to the next
+// hop, so a routed message never outlives the operation that sent it.
+type Timeouts struct {
+	RPC   time.Duration
+	Route time.Duration
+	Get   time.Duration
+	Put   time.Duration // Also bounds deletes
+}
+
+func (t Timeouts) withDefaults() Timeouts {
+	if t.RPC <= 0 {
+		t.RPC = DEFAULT_RPC_TIMEOUT
+	}
+	if t.Route <= 0 {
+		t.Route = DEFAULT_ROUTE_TIMEOUT
+	}
+	if t.Get <= 0 {
+		t.Get = DEFAULT_GET_TIMEOUT
+	}
+	if t.Put <= 0 {
+		t.Put = DEFAULT_PUT_TIMEOUT
+	}
+	return t
+}
+
+// withBudget bounds ctx by d. A zero d, as on nodes built without a config,
+// leaves ctx unbounded.
+func withBudget(ctx context.Context, d time.Duration) (context.Context, context.CancelFunc) {
+	if d <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, d)
+}
+
+func (n *Node) rpcContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	return withBudget(ctx, n.Timeouts.RPC)
+}
+
+func (n *Node) routeContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	return withBudget(ctx, n.Timeouts.Route)
+}
+
+// hopContext bounds one attempt to send a routed message to a next hop. Only
+// the last attempt may use the whole route budget; the others get half of
+// what is left, so a hop that hangs still leaves time to fail over.
+func (n *Node) hopContext(ctx context.Context, last bool) (context.Context, context.CancelFunc) {
+	ctx, cancel := n.routeContext(ctx)
+	deadline, ok := ctx.Deadline()
+	if last || !ok {
+		return ctx, cancel
+	}
+	hop, cancelHop := context.WithTimeout(ctx, time.Until(deadline)/2)
+	return hop, func() {
+		cancelHop()
+		cancel()
+	}
+}
@@ -0,0 +1,27 @@
+package node
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestWithBudget(t *testing.T) {
+	ctx, cancel := withBudget(context.Background(), 0)
+	defer cancel()
+	if _, ok := ctx.Deadline(); ok {
+		t.Errorf("A zero budget should leave the context unbounded")
+	}
+
+	parent, cancelParent := context.WithTimeout(context.Background(), time.Second)
+	defer cancelParent()
+	ctx, cancel = withBudget(parent, time.Hour)
+	defer cancel()
+	if deadline, _ := ctx.Deadline(); time.Until(deadline) > time.Second {
+		t.Errorf("The caller's earlier deadline should win, got %v", time.Until(deadline))
+	}
+
+	if got := (Timeouts{Get: time.Second}).withDefaults(); got.Get != time.Second || got.RPC != DEFAULT_RPC_TIMEOUT {
+		t.Errorf("Unexpected defaults: %+v", got)
+	}
+}
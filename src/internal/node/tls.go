@@ -0,0 +1,141 @@
+package node
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	"tapestry/internal/id"
+)
+
+// Credentials used by GetClient for every outgoing connection.
+var clientCreds = insecure.NewCredentials()
+
+// LoadTLSConfig reads a node certificate, its key and the CA that signs every
+// node in the mesh. The certificate's common name is the node ID.
+func LoadTLSConfig(certFile, keyFile, caFile string) (*tls.Config, error) {
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load key pair: %w", err)
+	}
+	caPEM, err := os.ReadFile(caFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CA %s: %w", caFile, err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(caPEM) {
+		return nil, fmt.Errorf("no certificates found in %s", caFile)
+	}
+	return &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		RootCAs:      pool,
+		ClientCAs:    pool,
+		MinVersion:   tls.VersionTLS12,
+	}, nil
+}
+
+// SetClientTLS makes GetClient dial with mutual TLS, for callers that are not
+// a node; nodes dial with their own Config.TLS. A nil config reverts to
+// plaintext. Existing connections are not affected.
+func SetClientTLS(cfg *tls.Config) {
+	if cfg == nil {
+		clientCreds = insecure.NewCredentials()
+		return
+	}
+	clientCreds = ClientCredentials(cfg)
+}
+
+// ClientCredentials dials with mutual TLS, for a ConnPool. Servers must
+// present a certificate from the mesh CA that names the node being dialed.
+func ClientCredentials(cfg *tls.Config) credentials.TransportCredentials {
+	return credentials.NewTLS(clientTLSConfig(cfg))
+}
+
+func serverTLSConfig(cfg *tls.Config) *tls.Config {
+	c := cfg.Clone()
+	c.ClientAuth = tls.RequireAndVerifyClientCert
+	return c
+}
+
+// clientTLSConfig checks the server chain against the mesh CA instead of a
+// host name: nodes are dialed by whatever address they advertise and are
+// identified by node ID, not DNS. ConnPool.GetNode dials with the expected
+// node ID as the server name, and the certificate must then name that node.
+func clientTLSConfig(cfg *tls.Config) *tls.Config {
+	c := cfg.Clone()
+	pool := c.RootCAs
+	c.InsecureSkipVerify = true
+	c.VerifyConnection = func(cs tls.ConnectionState) error {
+		certs := cs.PeerCertificates
+		if len(certs) == 0 {
+			return errors.New("peer presented no certificate")
+		}
+		intermediates := x509.NewCertPool()
+		for _, cert := range certs[1:] {
+			intermediates.AddCert(cert)
+		}
+		_, err := certs[0].Verify(x509.VerifyOptions{
+			Roots:         pool,
+			Intermediates: intermediates,
+			KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		})
+		if err != nil {
+			return err
+		}
+		if expected, err := id.Parse(cs.ServerName); err == nil && !certNames(certs[0], expected) {
+			return fmt.Errorf("certificate %q does not name node %s", certs[0].Subject.CommonName, expected)
+		}
+		return nil
+	}
+	return c
+}
+
+func certNames(cert *x509.Certificate, nodeID id.ID) bool {
+	return cert.Subject.CommonName == nodeID.String()
+}
+
+// checkCertIdentity fails unless the node's own certificate names nodeID.
+func checkCertIdentity(cfg *tls.Config, nodeID id.ID) error {
+	if len(cfg.Certificates) == 0 || len(cfg.Certificates[0].Certificate) == 0 {
+		return errors.New("no certificate configured")
+	}
+	leaf, err := x509.ParseCertificate(cfg.Certificates[0].Certificate[0])
+	if err != nil {
+		return err
+	}
+	if !certNames(leaf, nodeID) {
+		return fmt.Errorf("certificate %q does not name node %s", leaf.Subject.CommonName, nodeID)
+	}
+	return nil
+}
+
+// verifyPeerIdentity rejects a request that claims to come from claimed when
+// the caller's client certificate does not name it. Plaintext callers carry
+// no identity and are let through; a TLS node never accepts them.
+func verifyPeerIdentity(ctx context.Context, claimed id.ID) error {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return nil
+	}
+	info, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok {
+		return nil
+	}
+	if len(info.State.PeerCertificates) == 0 {
+		return status.Error(codes.Unauthenticated, "no client certificate")
+	}
+	if !certNames(info.State.PeerCertificates[0], claimed) {
+		return status.Errorf(codes.PermissionDenied, "certificate %q cannot act for node %s",
+			info.State.PeerCertificates[0].Subject.CommonName, claimed)
+	}
+	return nil
+}
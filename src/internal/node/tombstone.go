@@ -0,0 +1,63 @@
+package node
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+const TOMBSTONE_GRACE_PERIOD = 24 * time.Hour
+
+func unixNanos(ns int64) time.Time {
+	if ns == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, ns)
+}
+
+func toUnixNanos(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.UnixNano()
+}
+
+// visible applies deletes and expiry to a resolved object. A tombstone hides
+// the value unless a concurrent write survived it as a sibling, in which
+// case the write wins.
+func visible(obj Object) (Object, bool) {
+	var live []Object
+	for _, v := range obj.versions() {
+		if !v.Deleted && !v.Expired() {
+			live = append(live, v)
+		}
+	}
+	if len(live) == 0 {
+		return Object{}, false
+	}
+
+	winner := live[0]
+	winner.Owner = obj.Owner
+	if len(live) > 1 {
+		winner.Siblings = live[1:]
+	}
+	return winner, true
+}
+
+// CollectTombstones drops tombstones older than the grace period, which by
+// then have reached every replica, and stops advertising them. It returns
+// the number collected.
+func (n *Node) CollectTombstones() int {
+	collected := 0
+	for _, obj := range n.Objects.List() {
+		if !obj.Deleted || len(obj.Siblings) > 0 || time.Since(obj.DeletedAt) < n.TombstoneGrace {
+			continue
+		}
+		n.removeLocal(context.Background(), obj.Key)
+		collected++
+	}
+	if collected > 0 {
+		log.Printf("[STORE] Collected %d tombstones", collected)
+	}
+	return collected
+}
@@ -0,0 +1,139 @@
+package node
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	pb "tapestry/api/proto"
+	"tapestry/internal/id"
+)
+
+func (n *Node) TraceRoute(ctx context.Context, req *pb.TraceRequest) (*pb.TraceResponse, error) {
+	if req.TargetId == nil || len(req.TargetId.Bytes) != id.BYTES {
+		return nil, fmt.Errorf("invalid target ID")
+	}
+	var targetID id.ID
+	copy(targetID[:], req.TargetId.Bytes)
+
+	if req.HopLimit == 0 {
+		req.HopLimit = MAX_HOPS
+	}
+
+	d := n.computeRoute(targetID)
+
+	hop := &pb.TraceHop{
+		Node:      n.toProtoNeighbor(),
+		Level:     int32(id.SharedPrefixLength(n.ID, targetID)),
+		Surrogate: d.Surrogate,
+	}
+
+	if d.IsRoot || d.NextHop.ID.Equals(n.ID) {
+		return &pb.TraceResponse{Hops: []*pb.TraceHop{hop}, ReachedRoot: true}, nil
+	}
+	if req.HopLimit <= 1 {
+		return &pb.TraceResponse{Hops: []*pb.TraceHop{hop}, ReachedRoot: false}, nil
+	}
+
+	var rtt time.Duration
+	var resp *pb.TraceResponse
+	d, err := n.forward(ctx, targetID, func(ctx context.Context, client *TapestryClient, d routeDecision) error {
+		pingCtx, cancel := n.rpcContext(ctx)
+		defer cancel()
+		start := time.Now()
+		if _, err := client.Ping(pingCtx, &pb.Nothing{}); err != nil {
+			return err
+		}
+		rtt = time.Since(start)
+
+		var err error
+		resp, err = client.TraceRoute(ctx, &pb.TraceRequest{
+			TargetId: req.TargetId,
+			HopLimit: req.HopLimit - 1,
+		})
+		return err
+	})
+	if err != nil {
+		log.Printf("[TRACE] Forwarding towards %s failed: %v", targetID, err)
+		return &pb.TraceResponse{Hops: []*pb.TraceHop{hop}, ReachedRoot: false}, nil
+	}
+	// Failing over may have taken a surrogate, or made us the root.
+	hop.Surrogate = d.Surrogate
+	if d.IsRoot {
+		return &pb.TraceResponse{Hops: []*pb.TraceHop{hop}, ReachedRoot: true}, nil
+	}
+
+	if len(resp.Hops) > 0 {
+		resp.Hops[0].RttMicros = rtt.Microseconds()
+	}
+	resp.Hops = append([]*pb.TraceHop{hop}, resp.Hops...)
+	return resp, nil
+}
+
+func (n *Node) FindRoot(target id.ID) (Neighbor, error) {
+	return n.findRoot(context.Background(), target)
+}
+
+func (n *Node) findRoot(ctx context.Context, target id.ID) (Neighbor, error) {
+	ctx, cancel := n.routeContext(ctx)
+	defer cancel()
+	resp, err := n.TraceRoute(ctx, &pb.TraceRequest{
+		TargetId: &pb.NodeID{Bytes: target.Bytes()},
+		HopLimit: MAX_HOPS,
+	})
+	if err != nil {
+		return Neighbor{}, err
+	}
+	return rootFromTrace(resp)
+}
+
+func rootFromTrace(resp *pb.TraceResponse) (Neighbor, error) {
+	if !resp.ReachedRoot || len(resp.Hops) == 0 {
+		return Neighbor{}, fmt.Errorf("route did not reach a root")
+	}
+	return NeighborFromProto(resp.Hops[len(resp.Hops)-1].Node)
+}
+
+type RootReport struct {
+	Target id.ID
+	Roots  map[string]int // root ID -> number of origins that terminated there
+	Failed int
+}
+
+func (r RootReport) Unique() bool {
+	return len(r.Roots) == 1 && r.Failed == 0
+}
+
+// VerifyRoot traces a route to target from every given node address and
+// reports which roots the routes terminated at.
+func VerifyRoot(addrs []string, target id.ID) RootReport {
+	report := RootReport{Target: target, Roots: make(map[string]int)}
+
+	for _, addr := range addrs {
+		client, err := GetClient(addr)
+		if err != nil {
+			report.Failed++
+			continue
+		}
+		ctx, cancel := withBudget(context.Background(), DEFAULT_ROUTE_TIMEOUT)
+		resp, err := client.TraceRoute(ctx, &pb.TraceRequest{
+			TargetId: &pb.NodeID{Bytes: target.Bytes()},
+			HopLimit: MAX_HOPS,
+		})
+		cancel()
+		client.Close()
+		if err != nil {
+			report.Failed++
+			continue
+		}
+
+		root, err := rootFromTrace(resp)
+		if err != nil {
+			report.Failed++
+			continue
+		}
+		report.Roots[root.ID.String()]++
+	}
+	return report
+}
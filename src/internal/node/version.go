@@ -0,0 +1,237 @@
+package node
+
+import (
+	"bytes"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	pb "tapestry/api/proto"
+)
+
+// VectorClock counts the writes each node (by hex ID) has applied to an object.
+type VectorClock map[string]uint64
+
+type Ordering int
+
+const (
+	BEFORE Ordering = iota
+	AFTER
+	EQUAL
+	CONCURRENT
+)
+
+func (vc VectorClock) Copy() VectorClock {
+	c := make(VectorClock, len(vc))
+	for k, v := range vc {
+		c[k] = v
+	}
+	return c
+}
+
+func (vc VectorClock) Increment(nodeID string) VectorClock {
+	c := vc.Copy()
+	c[nodeID]++
+	return c
+}
+
+func (vc VectorClock) Merge(other VectorClock) VectorClock {
+	c := vc.Copy()
+	for k, v := range other {
+		if v > c[k] {
+			c[k] = v
+		}
+	}
+	return c
+}
+
+// Compare reports how vc relates to other: BEFORE means other supersedes vc.
+func (vc VectorClock) Compare(other VectorClock) Ordering {
+	less, greater := false, false
+	for k, v := range vc {
+		if v > other[k] {
+			greater = true
+		} else if v < other[k] {
+			less = true
+		}
+	}
+	for k, v := range other {
+		if _, ok := vc[k]; !ok && v > 0 {
+			less = true
+		}
+	}
+
+	switch {
+	case less && greater:
+		return CONCURRENT
+	case less:
+		return BEFORE
+	case greater:
+		return AFTER
+	}
+	return EQUAL
+}
+
+func (vc VectorClock) sum() uint64 {
+	var total uint64
+	for _, v := range vc {
+		total += v
+	}
+	return total
+}
+
+func (vc VectorClock) String() string {
+	keys := make([]string, 0, len(vc))
+	for k := range vc {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	parts := make([]string, 0, len(keys))
+	for _, k := range keys {
+		parts = append(parts, fmt.Sprintf("%s:%d", k, vc[k]))
+	}
+	return "{" + strings.Join(parts, ",") + "}"
+}
+
+func (vc VectorClock) toProto() *pb.Version {
+	return &pb.Version{Clock: vc}
+}
+
+func versionFromProto(v *pb.Version) VectorClock {
+	if v == nil {
+		return VectorClock{}
+	}
+	return VectorClock(v.Clock).Copy()
+}
+
+// versions flattens an object and its siblings into individual values.
+func (o Object) versions() []Object {
+	out := []Object{{
+		Key:       o.Key,
+		Data:      o.Data,
+		Chunks:    o.Chunks,
+		Size:      o.Size,
+		Version:   o.Version,
+		Owner:     o.Owner,
+		Deleted:   o.Deleted,
+		DeletedAt: o.DeletedAt,
+		ExpiresAt: o.ExpiresAt,
+	}}
+	for _, s := range o.Siblings {
+		s.Key = o.Key
+		s.Siblings = nil
+		out = append(out, s)
+	}
+	return out
+}
+
+// clock returns the merge of every version the object holds, i.e. the
+// causal context a new write to it must supersede.
+func (o Object) clock() VectorClock {
+	vc := o.Version.Copy()
+	for _, s := range o.Siblings {
+		vc = vc.Merge(s.Version)
+	}
+	return vc
+}
+
+// resolve combines two copies of an object, dropping every value whose
+// version is superseded. Values that remain concurrent are kept as siblings
+// of a deterministically chosen winner so every node resolves alike. So are
+// different values under the same clock, which two writes from a node that
+// found no copy to build on can produce.
+func resolve(a, b Object) Object {
+	candidates := append(a.versions(), b.versions()...)
+
+	var live []Object
+	for i, c := range candidates {
+		dominated := false
+		for j, o := range candidates {
+			if i == j {
+				continue
+			}
+			switch c.Version.Compare(o.Version) {
+			case BEFORE:
+				dominated = true
+			case EQUAL:
+				dominated = j < i && sameValue(c, o)
+			}
+			if dominated {
+				break
+			}
+		}
+		if !dominated {
+			live = append(live, c)
+		}
+	}
+
+	sort.Slice(live, func(i, j int) bool {
+		si, sj := live[i].Version.sum(), live[j].Version.sum()
+		if si != sj {
+			return si > sj
+		}
+		if vi, vj := live[i].Version.String(), live[j].Version.String(); vi != vj {
+			return vi > vj
+		}
+		if c := bytes.Compare(live[i].Data, live[j].Data); c != 0 {
+			return c > 0
+		}
+		if ci, cj := strings.Join(live[i].Chunks, ","), strings.Join(live[j].Chunks, ","); ci != cj {
+			return ci > cj
+		}
+		if live[i].Deleted != live[j].Deleted {
+			return live[i].Deleted
+		}
+		return live[i].ExpiresAt.After(live[j].ExpiresAt)
+	})
+
+	winner := live[0]
+	if winner.Owner == "" {
+		winner.Owner = a.Owner
+	}
+	winner.Refs = mergeRefs(a.Refs, b.Refs)
+	if len(live) > 1 {
+		winner.Siblings = live[1:]
+	}
+	return winner
+}
+
+func sameValue(a, b Object) bool {
+	return a.Deleted == b.Deleted && bytes.Equal(a.Data, b.Data) &&
+		slices.Equal(a.Chunks, b.Chunks) && a.ExpiresAt.Equal(b.ExpiresAt)
+}
+
+func siblingsToProto(siblings []Object) []*pb.Sibling {
+	var out []*pb.Sibling
+	for _, s := range siblings {
+		out = append(out, &pb.Sibling{
+			Data:      s.Data,
+			Chunks:    s.Chunks,
+			Size:      s.Size,
+			Version:   s.Version.toProto(),
+			Deleted:   s.Deleted,
+			DeletedAt: toUnixNanos(s.DeletedAt),
+			ExpiresAt: toUnixNanos(s.ExpiresAt),
+		})
+	}
+	return out
+}
+
+func siblingsFromProto(key string, siblings []*pb.Sibling) []Object {
+	var out []Object
+	for _, s := range siblings {
+		out = append(out, Object{
+			Key:       key,
+			Data:      s.Data,
+			Chunks:    s.Chunks,
+			Size:      s.Size,
+			Version:   versionFromProto(s.Version),
+			Deleted:   s.Deleted,
+			DeletedAt: unixNanos(s.DeletedAt),
+			ExpiresAt: unixNanos(s.ExpiresAt),
+		})
+	}
+	return out
+}
@@ -0,0 +1,116 @@
+package node
+
+import (
+	"testing"
+	"time"
+
+	"tapestry/internal/id"
+)
+
+func TestVectorClockCompare(t *testing.T) {
+	a := VectorClock{"a": 1}
+	b := a.Increment("a")
+	c := a.Increment("c")
+
+	cases := []struct {
+		x, y VectorClock
+		want Ordering
+	}{
+		{a, b, BEFORE},
+		{b, a, AFTER},
+		{b, b.Copy(), EQUAL},
+		{b, c, CONCURRENT},
+		{VectorClock{}, a, BEFORE},
+		{b.Merge(c), c, AFTER},
+	}
+	for i, tc := range cases {
+		if got := tc.x.Compare(tc.y); got != tc.want {
+			t.Errorf("Case %d: %v vs %v = %d, expected %d", i, tc.x, tc.y, got, tc.want)
+		}
+	}
+}
+
+func TestResolve(t *testing.T) {
+	old := Object{Key: "k", Data: []byte("old"), Version: VectorClock{"a": 1}}
+	newer := Object{Key: "k", Data: []byte("new"), Version: VectorClock{"a": 2}}
+
+	if got := resolve(newer, old); string(got.Data) != "new" || len(got.Siblings) != 0 {
+		t.Errorf("Stale value should be dropped, got %q with %d siblings", got.Data, len(got.Siblings))
+	}
+
+	other := Object{Key: "k", Data: []byte("other"), Version: VectorClock{"a": 1, "b": 1}}
+	x := resolve(newer, other)
+	y := resolve(other, newer)
+	if len(x.Siblings) != 1 {
+		t.Fatalf("Concurrent values should be kept as siblings, got %d", len(x.Siblings))
+	}
+	if string(x.Data) != string(y.Data) {
+		t.Errorf("Resolution depends on argument order: %q vs %q", x.Data, y.Data)
+	}
+
+	// Two writes from a node that saw no copy carry the same clock.
+	v1 := Object{Key: "k", Data: []byte("v1"), Version: VectorClock{"w": 1}}
+	v2 := Object{Key: "k", Data: []byte("v2"), Version: VectorClock{"w": 1}}
+	p, q := resolve(v1, v2), resolve(v2, v1)
+	if len(p.Siblings) != 1 || string(p.Data) != string(q.Data) {
+		t.Errorf("Different values under one clock should both be kept, got %q with %d siblings vs %q", p.Data, len(p.Siblings), q.Data)
+	}
+	if got := resolve(v1, v1); len(got.Siblings) != 0 {
+		t.Errorf("Identical copies should collapse, got %d siblings", len(got.Siblings))
+	}
+
+	merged := Object{Key: "k", Data: []byte("merged"), Version: x.clock().Increment("a")}
+	if got := resolve(x, merged); string(got.Data) != "merged" || len(got.Siblings) != 0 {
+		t.Errorf("Write over merged clock should supersede siblings, got %q with %d siblings", got.Data, len(got.Siblings))
+	}
+}
+
+func TestStaleReplicaDoesNotOverwrite(t *testing.T) {
+	n := &Node{ID: id.NewRandomID(), Objects: NewMemoryStore()}
+
+	first, _ := n.storeNewVersion(Object{Key: "k", Data: []byte("v1")}, nil)
+	n.storeNewVersion(Object{Key: "k", Data: []byte("v2")}, nil)
+
+	if err := n.storeLocal(first); err != nil {
+		t.Fatalf("storeLocal failed: %v", err)
+	}
+	got, _ := n.Objects.Get(id.Hash("k"))
+	if string(got.Data) != "v2" {
+		t.Errorf("Stale replica overwrote newer value, got %q", got.Data)
+	}
+}
+
+func TestBlockRefsMergeAcrossVersions(t *testing.T) {
+	n := &Node{ID: id.NewRandomID(), Objects: NewMemoryStore()}
+	then := time.Now().Add(-time.Hour)
+
+	first, _ := n.storeNewVersion(Object{Key: "block:x", Data: []byte("x"), Refs: map[string]time.Time{"a": then}}, nil)
+	newer := Object{Key: "block:x", Data: []byte("x"), Version: first.Version.Increment("other"), Refs: map[string]time.Time{"b": time.Now()}}
+	if err := n.storeLocal(newer); err != nil {
+		t.Fatalf("storeLocal failed: %v", err)
+	}
+	n.storeNewVersion(Object{Key: "block:x", Data: []byte("x"), Refs: map[string]time.Time{"a": time.Now()}}, nil)
+
+	got, _ := n.Objects.Get(id.Hash("block:x"))
+	if len(got.Refs) != 2 {
+		t.Fatalf("Back-references were dropped by newer versions: %v", got.Refs)
+	}
+	if !got.Refs["a"].After(then) {
+		t.Errorf("Back-reference to 'a' was not refreshed")
+	}
+}
+
+func TestVisible(t *testing.T) {
+	live := Object{Key: "k", Data: []byte("v"), Version: VectorClock{"a": 1}}
+	tomb := Object{Key: "k", Deleted: true, Version: VectorClock{"a": 2}}
+
+	if _, ok := visible(resolve(live, tomb)); ok {
+		t.Errorf("A newer tombstone should hide the value")
+	}
+
+	concurrent := Object{Key: "k", Data: []byte("w"), Version: VectorClock{"a": 1, "b": 1}}
+	got, ok := visible(resolve(tomb, concurrent))
+	if !ok || string(got.Data) != "w" {
+		t.Errorf("A write concurrent with a delete should survive, got %q (visible=%v)", got.Data, ok)
+	}
+}
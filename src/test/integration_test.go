@@ -1,11 +1,35 @@
 package test
 
 import (
+	"bytes"
+	"context"
+	"errors"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
 	"fmt"
+	"math/big"
+	mrand "math/rand"
+	"net"
+	"os"
+	"path/filepath"
 	"sync/atomic" 
 	"testing"
 	"time"
 
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+
+	pb "tapestry/api/proto"
+	"tapestry/internal/id"
 	"tapestry/internal/node"
 )
 
@@ -15,38 +39,41 @@ func getNextPort() int {
 	return int(atomic.AddInt32(&globalPort, 1))
 }
 
+func startNode(t *testing.T) *node.Node {
+	port := getNextPort()
+	n, err := node.NewNode(port)
+	if err != nil {
+		t.Fatalf("Failed to create node on port %d: %v", port, err)
+	}
+
+	go func() {
+		if err := n.Start(); err != nil {
+		}
+	}()
+	time.Sleep(50 * time.Millisecond)
+	return n
+}
+
+func joinNode(t *testing.T, n *node.Node, bootstrapAddr string) {
+	for attempt := 0; attempt < 3; attempt++ {
+		if err := n.Join([]string{bootstrapAddr}); err == nil {
+			return
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	t.Fatalf("Node %s failed to join %s", n.ID, bootstrapAddr)
+}
+
 func createCluster(t *testing.T, count int) []*node.Node {
 	var nodes []*node.Node
 	bootstrapAddr := ""
-	
 
 	for i := 0; i < count; i++ {
-		port := getNextPort()
-		n, err := node.NewNode(port)
-		if err != nil {
-			t.Fatalf("Failed to create node %d: %v", i, err)
-		}
-
-		go func() {
-			if err := n.Start(); err != nil {
-			}
-		}()
-		time.Sleep(50 * time.Millisecond)
-
+		n := startNode(t)
 		if i > 0 {
-			success := false
-			for attempt := 0; attempt < 3; attempt++ {
-				if err := n.Join([]string{bootstrapAddr}); err == nil {
-					success = true
-					break
-				}
-				time.Sleep(100 * time.Millisecond)
-			}
-			if !success {
-				t.Fatalf("Node %d failed to join %s", i, bootstrapAddr)
-			}
+			joinNode(t, n, bootstrapAddr)
 		} else {
-			bootstrapAddr = fmt.Sprintf("localhost:%d", port)
+			bootstrapAddr = n.Address
 		}
 
 		nodes = append(nodes, n)
@@ -89,7 +116,7 @@ func TestBasicDOLR(t *testing.T) {
 	data := "is-secure"
 
 	t.Log("Node 0 publishing...")
-	err := nodes[0].StoreAndPublish(key, data)
+	err := nodes[0].StoreAndPublish(key, []byte(data))
 	if err != nil {
 		t.Fatalf("Publish failed: %v", err)
 	}
@@ -102,7 +129,7 @@ func TestBasicDOLR(t *testing.T) {
 		t.Fatalf("Get failed: %v", err)
 	}
 
-	if obj.Data != data {
+	if string(obj.Data) != data {
 		t.Errorf("Data mismatch. Expected %s, got %s", data, obj.Data)
 	}
 }
@@ -114,7 +141,7 @@ func TestReplicationFailover(t *testing.T) {
 	key := "resilient-key"
 	data := "cannot-kill-me"
 
-	nodes[0].StoreAndPublish(key, data)
+	nodes[0].StoreAndPublish(key, []byte(data))
 	time.Sleep(2 * time.Second)
 
 	t.Log("Killing Primary Node 0...")
@@ -126,7 +153,7 @@ func TestReplicationFailover(t *testing.T) {
 		t.Fatalf("Failover failed. Could not retrieve object: %v", err)
 	}
 
-	if obj.Data != data {
+	if string(obj.Data) != data {
 		t.Errorf("Got wrong data: %s", obj.Data)
 	}
 	t.Log("Failover successful!")
@@ -142,7 +169,7 @@ func TestGracefulExitHandoff(t *testing.T) {
 	key := "handoff-key"
 	data := "take-this"
 
-	nodes[0].StoreAndPublish(key, data)
+	nodes[0].StoreAndPublish(key, []byte(data))
 	time.Sleep(1 * time.Second)
 
 	t.Log("Node 0 leaving gracefully...")
@@ -159,8 +186,1242 @@ func TestGracefulExitHandoff(t *testing.T) {
 		t.Fatalf("Handoff failed. Data lost: %v", err)
 	}
 
-	if obj.Data != data {
+	if string(obj.Data) != data {
 		t.Errorf("Corrupted data: %s", obj.Data)
 	}
 	t.Log("Graceful handoff successful!")
-}
\ No newline at end of file
+}
+
+func TestTraceRoute(t *testing.T) {
+	nodes := createCluster(t, 4)
+	defer stopCluster(nodes)
+
+	target := id.Hash("trace-me")
+	resp, err := nodes[0].TraceRoute(context.Background(), &pb.TraceRequest{
+		TargetId: &pb.NodeID{Bytes: target.Bytes()},
+	})
+	if err != nil {
+		t.Fatalf("TraceRoute failed: %v", err)
+	}
+	if len(resp.Hops) == 0 {
+		t.Fatalf("Trace returned no hops")
+	}
+	if !resp.ReachedRoot {
+		t.Errorf("Trace did not reach the root")
+	}
+
+	first, _ := node.NeighborFromProto(resp.Hops[0].Node)
+	if !first.ID.Equals(nodes[0].ID) {
+		t.Errorf("First hop should be the origin node")
+	}
+	for i, h := range resp.Hops {
+		t.Logf("Hop %d: %x level=%d surrogate=%v rtt=%dus", i, h.Node.Id.Bytes[:4], h.Level, h.Surrogate, h.RttMicros)
+	}
+}
+
+func TestJoinMulticast(t *testing.T) {
+	nodes := createCluster(t, 6)
+	defer stopCluster(nodes)
+
+	for _, a := range nodes {
+		for _, b := range nodes {
+			if a == b {
+				continue
+			}
+			level := id.SharedPrefixLength(a.ID, b.ID)
+			if len(a.Table.Get(level, b.ID.GetDigit(level))) == 0 {
+				t.Errorf("Node %s has a hole at [%d][%d] although %s exists", a.ID, level, b.ID.GetDigit(level), b.ID)
+			}
+		}
+	}
+}
+
+func TestMulticastReportsMissingAcks(t *testing.T) {
+	nodes := createCluster(t, 2)
+	defer stopCluster(nodes)
+	ctx := context.Background()
+
+	// A dead node is the only candidate for a slot of nodes[0]'s first row.
+	digit := 0
+	for digit == nodes[0].ID.GetDigit(0) || digit == nodes[1].ID.GetDigit(0) {
+		digit++
+	}
+	deadID := id.NewRandomID().SetDigit(0, digit)
+	l, err := net.Listen("tcp", "localhost:0")
+	if err != nil {
+		t.Fatalf("Failed to reserve a port: %v", err)
+	}
+	deadAddr := l.Addr().String()
+	l.Close()
+	nodes[0].Table.Add(node.Neighbor{ID: deadID, Address: deadAddr})
+
+	trace, err := nodes[1].TraceRoute(ctx, &pb.TraceRequest{TargetId: &pb.NodeID{Bytes: nodes[1].ID.Bytes()}})
+	if err != nil {
+		t.Fatalf("TraceRoute failed: %v", err)
+	}
+	req := &pb.MulticastRequest{NewNode: trace.Hops[0].Node, Level: 0}
+
+	if _, err := nodes[0].NotifyMulticast(ctx, req); err == nil {
+		t.Errorf("Multicast reported success although a branch never acked")
+	}
+	if !nodes[0].Table.IsSuspect(deadID) {
+		t.Fatalf("Unreachable branch was not marked suspect")
+	}
+	if _, err := nodes[0].NotifyMulticast(ctx, req); err != nil {
+		t.Errorf("Retried multicast did not go around the suspect: %v", err)
+	}
+}
+
+func TestRootUniqueness(t *testing.T) {
+	nodes := createCluster(t, 6)
+	defer stopCluster(nodes)
+
+	var addrs []string
+	for _, n := range nodes {
+		addrs = append(addrs, n.Address)
+	}
+
+	for i := 0; i < 50; i++ {
+		target := id.NewRandomID()
+		report := node.VerifyRoot(addrs, target)
+		if !report.Unique() {
+			t.Errorf("Target %s resolved to %d roots (%d failed traces)", target, len(report.Roots), report.Failed)
+		}
+	}
+}
+
+func TestJoinMigratesPointers(t *testing.T) {
+	nodes := createCluster(t, 4)
+	defer stopCluster(nodes)
+
+	newcomer := startNode(t)
+	defer newcomer.Stop()
+
+	var keys []string
+	for i := 0; i < 20; i++ {
+		keys = append(keys, fmt.Sprintf("migrate-%d", i))
+	}
+	// The newcomer may own only a sliver of the ID space, so also publish
+	// keys whose first salted ID shares a prefix with it.
+	for i, near := 0, 0; near < 3; i++ {
+		key := fmt.Sprintf("migrate-near-%d", i)
+		if id.SharedPrefixLength(id.Hash(key+"-0"), newcomer.ID) >= 2 {
+			keys = append(keys, key)
+			near++
+		}
+	}
+	for _, key := range keys {
+		nodes[0].StoreAndPublish(key, []byte("data"))
+	}
+	time.Sleep(1 * time.Second)
+
+	joinNode(t, newcomer, nodes[0].Address)
+
+	rooted := 0
+	for _, key := range keys {
+		for i := 0; i < node.SALT_COUNT; i++ {
+			target := id.Hash(fmt.Sprintf("%s-%d", key, i))
+			resp, err := newcomer.Lookup(context.Background(), &pb.LookupRequest{
+				ObjectId: &pb.NodeID{Bytes: target.Bytes()},
+			})
+			if err != nil || !resp.Found {
+				t.Errorf("Lookup for %s (salt %d) failed right after join", key, i)
+			}
+
+			// Where the newcomer is now root, the pointers must have migrated
+			// to it rather than being found further along the route.
+			hop, err := newcomer.GetNextHop(context.Background(), &pb.RouteRequest{
+				TargetId: &pb.NodeID{Bytes: target.Bytes()},
+			})
+			if err != nil || !hop.IsRoot {
+				continue
+			}
+			rooted++
+			local, err := newcomer.Lookup(context.Background(), &pb.LookupRequest{
+				ObjectId: &pb.NodeID{Bytes: target.Bytes()},
+				HopLimit: 1,
+			})
+			if err != nil || !local.Found {
+				t.Errorf("Newcomer is root for %s (salt %d) but holds no pointer", key, i)
+			}
+		}
+	}
+	if rooted == 0 {
+		t.Fatal("Newcomer became root for none of the published objects")
+	}
+}
+
+func TestLeaveHandsOffPointers(t *testing.T) {
+	nodes := createCluster(t, 5)
+	defer stopCluster(nodes[1:])
+
+	var keys []string
+	for i := 0; i < 20; i++ {
+		key := fmt.Sprintf("handoff-pointer-%d", i)
+		nodes[1].StoreAndPublish(key, []byte("data"))
+		keys = append(keys, key)
+	}
+	time.Sleep(1 * time.Second)
+
+	if err := nodes[0].Leave(); err != nil {
+		t.Fatalf("Leave failed: %v", err)
+	}
+	handedOff := nodes[0].GetHandedOffPointerCount()
+	if handedOff == 0 {
+		t.Fatal("Node 0 left without handing off any pointers")
+	}
+	t.Logf("Node 0 handed off %d pointers", handedOff)
+
+	byAddr := make(map[string]*node.Node)
+	for _, n := range nodes[1:] {
+		byAddr[n.Address] = n
+	}
+
+	for _, key := range keys {
+		for i := 0; i < node.SALT_COUNT; i++ {
+			target := id.Hash(fmt.Sprintf("%s-%d", key, i))
+			resp, err := nodes[2].Lookup(context.Background(), &pb.LookupRequest{
+				ObjectId: &pb.NodeID{Bytes: target.Bytes()},
+			})
+			if err != nil || !resp.Found {
+				t.Errorf("Lookup for %s (salt %d) failed after node 0 left", key, i)
+			}
+
+			// The root that took over must answer from its own pointers.
+			root, err := nodes[2].FindRoot(target)
+			if err != nil {
+				t.Errorf("No root for %s (salt %d) after node 0 left: %v", key, i, err)
+				continue
+			}
+			successor, ok := byAddr[root.Address]
+			if !ok {
+				t.Errorf("Root for %s (salt %d) is not a live node: %s", key, i, root.Address)
+				continue
+			}
+			local, err := successor.Lookup(context.Background(), &pb.LookupRequest{
+				ObjectId: &pb.NodeID{Bytes: target.Bytes()},
+				HopLimit: 1,
+			})
+			if err != nil || !local.Found {
+				t.Errorf("Successor %s holds no pointer for %s (salt %d)", root.Address, key, i)
+			}
+		}
+	}
+}
+
+func TestUnpublishRemovesPointers(t *testing.T) {
+	nodes := createCluster(t, 4)
+	defer stopCluster(nodes)
+
+	key := "short-lived"
+	nodes[0].StoreAndPublish(key, []byte("gone-soon"))
+	time.Sleep(1 * time.Second)
+
+	if _, err := nodes[2].Get(key); err != nil {
+		t.Fatalf("Get before unpublish failed: %v", err)
+	}
+
+	nodes[0].Remove(key)
+
+	for i, n := range nodes {
+		if obj, err := n.Get(key); err == nil {
+			t.Errorf("Node %d still returns the object after remove: %s", i, obj.Data)
+		}
+	}
+
+	for _, n := range nodes {
+		n.TombstoneGrace = 0
+		n.CollectTombstones()
+	}
+
+	for i := 0; i < node.SALT_COUNT; i++ {
+		target := id.Hash(fmt.Sprintf("%s-%d", key, i))
+		resp, err := nodes[3].Lookup(context.Background(), &pb.LookupRequest{
+			ObjectId: &pb.NodeID{Bytes: target.Bytes()},
+		})
+		if err == nil && resp.Found {
+			t.Errorf("Salt %d still has %d publishers after tombstones were collected", i, len(resp.Publishers))
+		}
+	}
+	for i, n := range nodes {
+		if _, ok := n.Objects.Get(id.Hash(key)); ok {
+			t.Errorf("Node %d still stores the key after collection", i)
+		}
+	}
+}
+
+func TestLargeBinaryObject(t *testing.T) {
+	nodes := createCluster(t, 4)
+	defer stopCluster(nodes)
+
+	data := make([]byte, 3*node.CHUNK_SIZE+17)
+	mrand.New(mrand.NewSource(1)).Read(data)
+	data[0], data[1] = 0x00, 0xff
+
+	key := "big-blob"
+	if err := nodes[0].StoreAndPublish(key, data); err != nil {
+		t.Fatalf("Publish failed: %v", err)
+	}
+	time.Sleep(1 * time.Second)
+
+	obj, err := nodes[3].Get(key)
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if len(obj.Chunks) != 4 {
+		t.Errorf("Expected 4 blocks, got %d", len(obj.Chunks))
+	}
+	if !bytes.Equal(obj.Data, data) {
+		t.Errorf("Reassembled data differs: got %d bytes, expected %d", len(obj.Data), len(data))
+	}
+}
+
+func TestNewestVersionWins(t *testing.T) {
+	nodes := createCluster(t, 5)
+	defer stopCluster(nodes)
+
+	key := "versioned"
+	nodes[0].StoreAndPublish(key, []byte("v1"))
+	time.Sleep(1 * time.Second)
+	nodes[0].StoreAndPublish(key, []byte("v2"))
+	time.Sleep(1 * time.Second)
+
+	for i, n := range nodes {
+		obj, err := n.Get(key)
+		if err != nil {
+			t.Fatalf("Get from node %d failed: %v", i, err)
+		}
+		if string(obj.Data) != "v2" || len(obj.Siblings) != 0 {
+			t.Errorf("Node %d returned %q (version %v, %d siblings), expected v2", i, obj.Data, obj.Version, len(obj.Siblings))
+		}
+	}
+}
+
+func TestQuorumWrites(t *testing.T) {
+	nodes := createCluster(t, 4)
+	defer stopCluster(nodes)
+
+	key := "quorum"
+	if err := nodes[0].StoreAndPublishWithOptions(context.Background(), key, []byte("acked"), node.WriteOptions{W: node.REPLICATION_FACTOR}); err != nil {
+		t.Fatalf("Quorum write failed: %v", err)
+	}
+
+	holders := 0
+	for _, n := range nodes {
+		if _, ok := n.Objects.Get(id.Hash(key)); ok {
+			holders++
+		}
+	}
+	if holders < node.REPLICATION_FACTOR {
+		t.Errorf("Write returned with only %d/%d copies stored", holders, node.REPLICATION_FACTOR)
+	}
+
+	if err := nodes[0].StoreAndPublishWithOptions(context.Background(), key, []byte("x"), node.WriteOptions{W: node.REPLICATION_FACTOR + 1}); err == nil {
+		t.Errorf("Write quorum above the replication factor should be rejected")
+	}
+
+	time.Sleep(1 * time.Second)
+	if _, err := nodes[3].GetWithOptions(context.Background(), key, node.ReadOptions{R: node.REPLICATION_FACTOR}); err != nil {
+		t.Errorf("Quorum read failed: %v", err)
+	}
+}
+
+func TestQuorumUnreachable(t *testing.T) {
+	nodes := createCluster(t, 1)
+	defer stopCluster(nodes)
+
+	if err := nodes[0].StoreAndPublishWithOptions(context.Background(), "lonely", []byte("x"), node.WriteOptions{W: 2}); err == nil {
+		t.Errorf("Write quorum of 2 should fail without neighbors")
+	}
+	if _, err := nodes[0].GetWithOptions(context.Background(), "lonely", node.ReadOptions{R: 2}); err == nil {
+		t.Errorf("Read quorum of 2 should fail with a single copy")
+	}
+}
+
+func TestDeterministicPlacement(t *testing.T) {
+	nodes := createCluster(t, 6)
+	defer stopCluster(nodes)
+
+	key := "placed"
+	expected := nodes[0].ReplicaSet(key)
+	if len(expected) == 0 {
+		t.Fatalf("Empty replica set")
+	}
+	for i, n := range nodes[1:] {
+		got := n.ReplicaSet(key)
+		if len(got) != len(expected) {
+			t.Fatalf("Node %d computed %d replicas, node 0 computed %d", i+1, len(got), len(expected))
+		}
+		for j := range got {
+			if !got[j].ID.Equals(expected[j].ID) {
+				t.Errorf("Node %d disagrees on replica %d: %s vs %s", i+1, j, got[j].ID, expected[j].ID)
+			}
+		}
+	}
+
+	// Write from outside the set, which must not keep a copy of its own.
+	var writer *node.Node
+	for _, n := range nodes {
+		inSet := false
+		for _, replica := range expected {
+			inSet = inSet || replica.ID.Equals(n.ID)
+		}
+		if !inSet {
+			writer = n
+			break
+		}
+	}
+	if err := writer.StoreAndPublishWithOptions(context.Background(), key, []byte("here"), node.WriteOptions{W: len(expected)}); err != nil {
+		t.Fatalf("Publish failed: %v", err)
+	}
+	time.Sleep(500 * time.Millisecond)
+
+	for _, replica := range expected {
+		for _, n := range nodes {
+			if n.ID.Equals(replica.ID) {
+				if _, ok := n.Objects.Get(id.Hash(key)); !ok {
+					t.Errorf("Replica %s does not hold the object", replica.ID)
+				}
+			}
+		}
+	}
+	if _, ok := writer.Objects.Get(id.Hash(key)); ok {
+		t.Errorf("Writer outside the replica set kept a copy")
+	}
+}
+
+func TestRepairReplicas(t *testing.T) {
+	nodes := createCluster(t, 6)
+	defer stopCluster(nodes)
+
+	key := "fragile"
+	if err := nodes[0].StoreAndPublishWithOptions(context.Background(), key, []byte("keep-me"), node.WriteOptions{W: node.REPLICATION_FACTOR}); err != nil {
+		t.Fatalf("Publish failed: %v", err)
+	}
+	time.Sleep(500 * time.Millisecond)
+
+	var owner *node.Node
+	for _, n := range nodes {
+		if obj, ok := n.Objects.Get(id.Hash(key)); ok && obj.Owner == n.ID.String() {
+			owner = n
+		}
+	}
+	if owner == nil {
+		t.Fatalf("No node owns '%s'", key)
+	}
+
+	holders := func() []*node.Node {
+		var out []*node.Node
+		for _, n := range nodes {
+			if _, ok := n.Objects.Get(id.Hash(key)); ok && n != owner {
+				out = append(out, n)
+			}
+		}
+		return out
+	}
+
+	for len(holders())+1 >= node.REPLICATION_FACTOR {
+		victim := holders()[0]
+		victim.Stop()
+		var alive []*node.Node
+		for _, n := range nodes {
+			if n != victim {
+				alive = append(alive, n)
+			}
+		}
+		nodes = alive
+	}
+
+	if created := owner.RepairReplicas(); created == 0 {
+		t.Errorf("Expected the owner to create a replacement replica")
+	}
+	if got := len(holders()) + 1; got < node.REPLICATION_FACTOR {
+		t.Errorf("Only %d/%d copies after repair", got, node.REPLICATION_FACTOR)
+	}
+}
+
+func TestHolderRepairsAfterOwnerLeaves(t *testing.T) {
+	nodes := createCluster(t, 6)
+	defer func() { stopCluster(nodes) }()
+
+	key := "orphaned"
+	if err := nodes[0].StoreAndPublishWithOptions(context.Background(), key, []byte("adopt-me"), node.WriteOptions{W: node.REPLICATION_FACTOR}); err != nil {
+		t.Fatalf("Publish failed: %v", err)
+	}
+	time.Sleep(500 * time.Millisecond)
+
+	var owner *node.Node
+	for _, n := range nodes {
+		if obj, ok := n.Objects.Get(id.Hash(key)); ok && obj.Owner == n.ID.String() {
+			owner = n
+		}
+	}
+	if owner == nil {
+		t.Fatalf("No node owns '%s'", key)
+	}
+	owner.Stop()
+	var alive []*node.Node
+	for _, n := range nodes {
+		if n != owner {
+			alive = append(alive, n)
+		}
+	}
+	nodes = alive
+
+	// A holder may first hand its copy to an earlier member of the set,
+	// which takes over on its own pass.
+	var newOwner *node.Node
+	for pass := 0; pass < 3 && newOwner == nil; pass++ {
+		for _, n := range nodes {
+			n.RepairReplicas()
+		}
+		for _, n := range nodes {
+			if obj, ok := n.Objects.Get(id.Hash(key)); ok && obj.Owner == n.ID.String() {
+				newOwner = n
+			}
+		}
+	}
+	if newOwner == nil {
+		t.Fatalf("No holder took over '%s' after its owner left", key)
+	}
+
+	copies := 0
+	for _, n := range nodes {
+		if _, ok := n.Objects.Get(id.Hash(key)); ok {
+			copies++
+		}
+	}
+	if copies < node.REPLICATION_FACTOR {
+		t.Errorf("Only %d/%d copies after the new owner repaired", copies, node.REPLICATION_FACTOR)
+	}
+}
+
+func TestAntiEntropyRepairsReplicas(t *testing.T) {
+	nodes := createCluster(t, 5)
+	defer stopCluster(nodes)
+
+	for i := 0; i < 10; i++ {
+		key := fmt.Sprintf("entropy-%d", i)
+		if err := nodes[0].StoreAndPublishWithOptions(context.Background(), key, []byte("v1"), node.WriteOptions{W: node.REPLICATION_FACTOR}); err != nil {
+			t.Fatalf("Publish failed: %v", err)
+		}
+	}
+	time.Sleep(500 * time.Millisecond)
+
+	if repaired := nodes[0].RunAntiEntropy(); repaired != 0 {
+		t.Errorf("In-sync replicas reported %d repaired keys", repaired)
+	}
+
+	// Wipe one replica behind the owner's back.
+	var victim *node.Node
+	var lost []node.Object
+	for _, n := range nodes[1:] {
+		for _, obj := range n.Objects.List() {
+			if obj.Owner == nodes[0].ID.String() {
+				victim = n
+				lost = append(lost, obj)
+				n.Objects.Delete(id.Hash(obj.Key))
+			}
+		}
+		if victim != nil {
+			break
+		}
+	}
+	if victim == nil {
+		t.Fatalf("No replica holder found")
+	}
+
+	if repaired := nodes[0].RunAntiEntropy(); repaired < len(lost) {
+		t.Errorf("Expected at least %d repaired keys, got %d", len(lost), repaired)
+	}
+	for _, obj := range lost {
+		if _, ok := victim.Objects.Get(id.Hash(obj.Key)); !ok {
+			t.Errorf("'%s' was not restored on the wiped replica", obj.Key)
+		}
+	}
+}
+
+func TestAntiEntropyBatchesLargeRepairs(t *testing.T) {
+	nodes := createCluster(t, 5)
+	defer stopCluster(nodes)
+
+	// Enough chunk blocks that a single repair message would exceed gRPC's
+	// 4MB limit.
+	data := make([]byte, 20<<20)
+	mrand.Read(data)
+	if err := nodes[0].StoreAndPublishWithOptions(context.Background(), "bulky", data, node.WriteOptions{}); err != nil {
+		t.Fatalf("Publish failed: %v", err)
+	}
+	time.Sleep(500 * time.Millisecond)
+
+	var victim *node.Node
+	var lost []node.Object
+	for _, n := range nodes[1:] {
+		for _, obj := range n.Objects.List() {
+			if obj.Owner == nodes[0].ID.String() {
+				victim = n
+				lost = append(lost, obj)
+				n.Objects.Delete(id.Hash(obj.Key))
+			}
+		}
+		if victim != nil {
+			break
+		}
+	}
+	if victim == nil {
+		t.Fatalf("No replica holder found")
+	}
+
+	if repaired := nodes[0].RunAntiEntropy(); repaired < len(lost) {
+		t.Errorf("Expected at least %d repaired keys, got %d", len(lost), repaired)
+	}
+	for _, obj := range lost {
+		if _, ok := victim.Objects.Get(id.Hash(obj.Key)); !ok {
+			t.Errorf("'%s' was not restored on the wiped replica", obj.Key)
+		}
+	}
+}
+
+func TestReadRepair(t *testing.T) {
+	nodes := createCluster(t, 5)
+	defer stopCluster(nodes)
+
+	key := "repair-on-read"
+	if err := nodes[0].StoreAndPublishWithOptions(context.Background(), key, []byte("fresh"), node.WriteOptions{W: node.REPLICATION_FACTOR}); err != nil {
+		t.Fatalf("Publish failed: %v", err)
+	}
+	time.Sleep(1 * time.Second)
+
+	var lagging *node.Node
+	for _, n := range nodes[1:] {
+		if _, ok := n.Objects.Get(id.Hash(key)); ok {
+			lagging = n
+			break
+		}
+	}
+	if lagging == nil {
+		t.Fatalf("No replica holder found")
+	}
+	lagging.Objects.Put(id.Hash(key), node.Object{Key: key, Data: []byte("stale"), Size: 5})
+
+	obj, err := nodes[0].GetWithOptions(context.Background(), key, node.ReadOptions{ReadRepair: true})
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if string(obj.Data) != "fresh" {
+		t.Errorf("Get returned %q, expected fresh", obj.Data)
+	}
+
+	time.Sleep(500 * time.Millisecond)
+	if got, _ := lagging.Objects.Get(id.Hash(key)); string(got.Data) != "fresh" {
+		t.Errorf("Lagging replica still holds %q after read repair", got.Data)
+	}
+}
+
+type fixedPlacement []node.Neighbor
+
+func (f fixedPlacement) Replicas(n *node.Node, key string, count int) []node.Neighbor {
+	return f
+}
+
+// localPlacement keeps the only copy on the writer.
+type localPlacement struct{}
+
+func (localPlacement) Replicas(n *node.Node, key string, count int) []node.Neighbor {
+	return []node.Neighbor{{ID: n.ID, Address: n.Address}}
+}
+
+func TestHintedHandoff(t *testing.T) {
+	downPort := getNextPort()
+	downPub, downKey, _ := ed25519.GenerateKey(nil)
+	downID := node.KeyID(downPub)
+	down := node.Neighbor{ID: downID, Address: fmt.Sprintf("localhost:%d", downPort)}
+
+	writer, err := node.NewNodeWithConfig(node.Config{Port: getNextPort(), Placement: fixedPlacement{down}})
+	if err != nil {
+		t.Fatalf("Failed to create writer: %v", err)
+	}
+	go writer.Start()
+	defer writer.Stop()
+
+	key := "hinted"
+	writer.StoreAndPublish(key, []byte("deliver-later"))
+	for deadline := time.Now().Add(10 * time.Second); time.Now().Before(deadline); time.Sleep(100 * time.Millisecond) {
+		if writer.MetricsSnapshot().HintsQueued > 0 {
+			break
+		}
+	}
+
+	if m := writer.MetricsSnapshot(); m.HintsQueued != 1 || m.HintsPending != 1 {
+		t.Fatalf("Expected 1 queued hint, got queued=%d pending=%d", m.HintsQueued, m.HintsPending)
+	}
+
+	target, err := node.NewNodeWithConfig(node.Config{Port: downPort, Key: downKey})
+	if err != nil {
+		t.Fatalf("Failed to start target: %v", err)
+	}
+	go target.Start()
+	defer target.Stop()
+	time.Sleep(100 * time.Millisecond)
+
+	if replayed := writer.ReplayHints(); replayed != 1 {
+		t.Errorf("Expected 1 replayed hint, got %d", replayed)
+	}
+	if obj, ok := target.Objects.Get(id.Hash(key)); !ok || string(obj.Data) != "deliver-later" {
+		t.Errorf("Target did not receive the hinted write")
+	}
+	if m := writer.MetricsSnapshot(); m.HintsReplayed != 1 || m.HintsPending != 0 {
+		t.Errorf("Expected 1 replayed and 0 pending hints, got replayed=%d pending=%d", m.HintsReplayed, m.HintsPending)
+	}
+}
+
+func TestAbandonedWriteQueuesNoHints(t *testing.T) {
+	// The replica accepts connections but never answers.
+	blackhole, err := net.Listen("tcp", "localhost:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	defer blackhole.Close()
+	go func() {
+		for {
+			conn, err := blackhole.Accept()
+			if err != nil {
+				return
+			}
+			defer conn.Close()
+		}
+	}()
+	_, hungKey, _ := ed25519.GenerateKey(nil)
+	hung := node.Neighbor{ID: node.KeyID(hungKey.Public().(ed25519.PublicKey)), Address: blackhole.Addr().String()}
+
+	writer, err := node.NewNodeWithConfig(node.Config{Port: getNextPort(), Placement: fixedPlacement{hung}})
+	if err != nil {
+		t.Fatalf("Failed to create writer: %v", err)
+	}
+	go writer.Start()
+	defer writer.Stop()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(200*time.Millisecond, cancel)
+	if err := writer.StoreAndPublishWithOptions(ctx, "abandoned", []byte("x"), node.WriteOptions{}); !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected the write to fail with its context, got %v", err)
+	}
+	time.Sleep(500 * time.Millisecond)
+	if m := writer.MetricsSnapshot(); m.HintsQueued != 0 {
+		t.Errorf("Abandoned write queued %d hints", m.HintsQueued)
+	}
+}
+
+func TestTombstoneSuppressesStaleReplica(t *testing.T) {
+	nodes := createCluster(t, 5)
+	defer stopCluster(nodes)
+
+	key := "deleted-everywhere"
+	if err := nodes[0].StoreAndPublishWithOptions(context.Background(), key, []byte("old"), node.WriteOptions{W: node.REPLICATION_FACTOR}); err != nil {
+		t.Fatalf("Publish failed: %v", err)
+	}
+	time.Sleep(500 * time.Millisecond)
+
+	var stale *node.Node
+	var before node.Object
+	for _, n := range nodes[1:] {
+		if obj, ok := n.Objects.Get(id.Hash(key)); ok {
+			stale, before = n, obj
+			break
+		}
+	}
+	if stale == nil {
+		t.Fatalf("No replica holder found")
+	}
+
+	nodes[0].Remove(key)
+
+	// The replica missed the delete.
+	stale.Objects.Put(id.Hash(key), before)
+
+	// A single-copy read may land on the stale replica; a full one must not.
+	for i, n := range nodes {
+		if _, err := n.GetWithOptions(context.Background(), key, node.ReadOptions{R: node.REPLICATION_FACTOR}); err == nil {
+			t.Errorf("Node %d resurrected the deleted value", i)
+		}
+	}
+
+	nodes[0].RunAntiEntropy()
+	if obj, _ := stale.Objects.Get(id.Hash(key)); !obj.Deleted {
+		t.Errorf("Anti-entropy did not spread the tombstone to the stale replica")
+	}
+}
+
+func TestRemoveCollectsChunkBlocks(t *testing.T) {
+	nodes := createCluster(t, 4)
+	defer stopCluster(nodes)
+
+	data := make([]byte, 3*node.CHUNK_SIZE)
+	mrand.Read(data)
+	key := "chunked-delete"
+	if err := nodes[0].StoreAndPublish(key, data); err != nil {
+		t.Fatalf("Publish failed: %v", err)
+	}
+	time.Sleep(500 * time.Millisecond)
+
+	manifest, err := nodes[0].Get(key)
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if len(manifest.Chunks) == 0 {
+		t.Fatalf("Object was not chunked")
+	}
+
+	nodes[0].Remove(key)
+	for _, n := range nodes {
+		n.BlockGrace = 0
+		n.CollectBlocks()
+	}
+	for i, n := range nodes {
+		for _, bk := range manifest.Chunks {
+			if _, ok := n.Objects.Get(id.Hash(bk)); ok {
+				t.Errorf("Node %d still stores block %s after collection", i, bk)
+			}
+		}
+	}
+}
+
+func TestRemoveKeepsSharedBlocks(t *testing.T) {
+	nodes := createCluster(t, 5)
+	defer stopCluster(nodes)
+
+	shared := make([]byte, node.CHUNK_SIZE)
+	ownA := make([]byte, node.CHUNK_SIZE)
+	ownB := make([]byte, node.CHUNK_SIZE)
+	mrand.Read(shared)
+	mrand.Read(ownA)
+	mrand.Read(ownB)
+	dataB := append(append([]byte{}, shared...), ownB...)
+
+	if err := nodes[0].StoreAndPublish("shares-a", append(append([]byte{}, shared...), ownA...)); err != nil {
+		t.Fatalf("Publish of A failed: %v", err)
+	}
+	if err := nodes[1].StoreAndPublish("shares-b", dataB); err != nil {
+		t.Fatalf("Publish of B failed: %v", err)
+	}
+	time.Sleep(500 * time.Millisecond)
+
+	// Remove A from a node that does not hold B's manifest, which is the one
+	// that cannot tell locally that the shared block is still in use.
+	var remover *node.Node
+	for _, n := range nodes {
+		if _, ok := n.Objects.Get(id.Hash("shares-b")); !ok {
+			remover = n
+			break
+		}
+	}
+	if remover == nil {
+		t.Fatalf("Every node holds B's manifest")
+	}
+	remover.Remove("shares-a")
+
+	for _, n := range nodes {
+		n.BlockGrace = 0
+		n.CollectBlocks()
+	}
+
+	obj, err := nodes[2].Get("shares-b")
+	if err != nil {
+		t.Fatalf("Get of B failed after removing A: %v", err)
+	}
+	if !bytes.Equal(obj.Data, dataB) {
+		t.Errorf("B came back corrupted after removing A")
+	}
+}
+
+func TestObjectTTL(t *testing.T) {
+	nodes := createCluster(t, 5)
+	defer stopCluster(nodes)
+
+	key := "short-lived"
+	if err := nodes[0].StoreAndPublishWithOptions(context.Background(), key, []byte("soon gone"), node.WriteOptions{W: node.REPLICATION_FACTOR, TTL: time.Second}); err != nil {
+		t.Fatalf("Publish failed: %v", err)
+	}
+	time.Sleep(500 * time.Millisecond)
+
+	if obj, err := nodes[4].Get(key); err != nil || string(obj.Data) != "soon gone" {
+		t.Fatalf("Get before expiry failed: %v", err)
+	}
+
+	time.Sleep(time.Second)
+	for i, n := range nodes {
+		if _, err := n.Get(key); err == nil {
+			t.Errorf("Node %d returned an expired object", i)
+		}
+	}
+
+	for i, n := range nodes {
+		n.SweepExpired()
+		if _, ok := n.Objects.Get(id.Hash(key)); ok {
+			t.Errorf("Node %d still stores the expired object after a sweep", i)
+		}
+	}
+}
+
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	pem  []byte
+}
+
+func newTestCA(t *testing.T) *testCA {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "tapestry test CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, _ := x509.ParseCertificate(der)
+	return &testCA{cert: cert, key: key, pem: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})}
+}
+
+// issue writes a node certificate for cn (with extra DNS names) and loads it
+// with the node's TLS loader.
+func (ca *testCA) issue(t *testing.T, cn string, extra ...string) *tls.Config {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: cn},
+		DNSNames:     extra,
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, ca.cert, &key.PublicKey, ca.key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+	certFile := filepath.Join(dir, "node.crt")
+	keyFile := filepath.Join(dir, "node.key")
+	caFile := filepath.Join(dir, "ca.crt")
+	os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600)
+	os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0600)
+	os.WriteFile(caFile, ca.pem, 0600)
+
+	cfg, err := node.LoadTLSConfig(certFile, keyFile, caFile)
+	if err != nil {
+		t.Fatalf("LoadTLSConfig failed: %v", err)
+	}
+	return cfg
+}
+
+func dialWith(t *testing.T, address string, creds credentials.TransportCredentials) pb.NodeServiceClient {
+	conn, err := grpc.NewClient(address, grpc.WithTransportCredentials(creds))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return pb.NewNodeServiceClient(conn)
+}
+
+func TestMutualTLS(t *testing.T) {
+	ca := newTestCA(t)
+	var keys []ed25519.PrivateKey
+	var ids []id.ID
+	var names []string
+	for i := 0; i < 3; i++ {
+		pub, key, _ := ed25519.GenerateKey(nil)
+		keys = append(keys, key)
+		ids = append(ids, node.KeyID(pub))
+		names = append(names, ids[i].String())
+	}
+	var nodes []*node.Node
+	for i, key := range keys {
+		n, err := node.NewNodeWithConfig(node.Config{Port: getNextPort(), Key: key, TLS: ca.issue(t, names[i])})
+		if err != nil {
+			t.Fatalf("Failed to create TLS node: %v", err)
+		}
+		go n.Start()
+		time.Sleep(50 * time.Millisecond)
+		if i > 0 {
+			joinNode(t, n, nodes[0].Address)
+		}
+		nodes = append(nodes, n)
+	}
+	defer stopCluster(nodes)
+	time.Sleep(time.Second)
+
+	if err := nodes[1].StoreAndPublish("secure", []byte("over tls")); err != nil {
+		t.Fatalf("Publish over TLS failed: %v", err)
+	}
+	time.Sleep(200 * time.Millisecond)
+	if obj, err := nodes[2].Get("secure"); err != nil || string(obj.Data) != "over tls" {
+		t.Fatalf("Get over TLS failed: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	target := nodes[0].Address
+	skipVerify := func(cfg *tls.Config) credentials.TransportCredentials {
+		c := cfg.Clone()
+		c.InsecureSkipVerify = true
+		return credentials.NewTLS(c)
+	}
+
+	if _, err := dialWith(t, target, insecure.NewCredentials()).Ping(ctx, &pb.Nothing{}); err == nil {
+		t.Errorf("Plaintext client was accepted")
+	}
+
+	rogue := newTestCA(t).issue(t, names[1])
+	if _, err := dialWith(t, target, skipVerify(rogue)).Ping(ctx, &pb.Nothing{}); err == nil {
+		t.Errorf("Client with an untrusted certificate was accepted")
+	}
+
+	other := dialWith(t, target, skipVerify(ca.issue(t, id.NewRandomID().String())))
+	if _, err := other.Ping(ctx, &pb.Nothing{}); err != nil {
+		t.Fatalf("Trusted client was rejected: %v", err)
+	}
+	// A replayed, validly signed advertisement is still not proof of identity.
+	trace, err := nodes[1].TraceRoute(ctx, &pb.TraceRequest{TargetId: &pb.NodeID{Bytes: ids[1].Bytes()}})
+	if err != nil {
+		t.Fatalf("TraceRoute failed: %v", err)
+	}
+	impersonated := trace.Hops[0].Node
+	if _, err := other.NotifyLeave(ctx, impersonated); status.Code(err) != codes.PermissionDenied {
+		t.Errorf("NotifyLeave for another node should be denied, got %v", err)
+	}
+	if _, err := other.AddBackpointer(ctx, &pb.BackpointerRequest{From: impersonated}); status.Code(err) != codes.PermissionDenied {
+		t.Errorf("AddBackpointer for another node should be denied, got %v", err)
+	}
+	// Listing an ID among the DNS names does not make a certificate name it.
+	aliased := dialWith(t, target, skipVerify(ca.issue(t, id.NewRandomID().String(), names[1])))
+	if _, err := aliased.NotifyLeave(ctx, impersonated); status.Code(err) != codes.PermissionDenied {
+		t.Errorf("NotifyLeave through a DNS name alias should be denied, got %v", err)
+	}
+
+	// Clients hold the server to the node ID they dialed.
+	pool := node.NewConnPool(node.ConnPoolConfig{Creds: node.ClientCredentials(ca.issue(t, names[1]))})
+	defer pool.Close()
+	right, _ := pool.GetNode(target, ids[0])
+	defer right.Close()
+	if _, err := right.Ping(ctx, &pb.Nothing{}); err != nil {
+		t.Errorf("Dial of the node its certificate names failed: %v", err)
+	}
+	wrong, _ := pool.GetNode(target, ids[2])
+	defer wrong.Close()
+	if _, err := wrong.Ping(ctx, &pb.Nothing{}); err == nil {
+		t.Errorf("Server was accepted as a node its certificate does not name")
+	}
+
+	if _, err := node.NewNodeWithConfig(node.Config{Port: getNextPort(), TLS: ca.issue(t, names[0])}); err == nil {
+		t.Errorf("Node started with a certificate that does not name it")
+	}
+}
+
+func TestForgedRoutingMessagesRejected(t *testing.T) {
+	nodes := createCluster(t, 3)
+	defer stopCluster(nodes)
+	ctx := context.Background()
+
+	objID := id.Hash("forged-0")
+	claimed := &pb.Neighbor{Id: &pb.NodeID{Bytes: id.NewRandomID().Bytes()}, Address: "localhost:1"}
+	if _, err := nodes[0].Publish(ctx, &pb.PublishRequest{ObjectId: &pb.NodeID{Bytes: objID.Bytes()}, Publisher: claimed}); err == nil {
+		t.Errorf("Publish from an unsigned neighbor was accepted")
+	}
+	if _, err := nodes[0].AddBackpointer(ctx, &pb.BackpointerRequest{From: claimed}); err == nil {
+		t.Errorf("Backpointer from an unsigned neighbor was accepted")
+	}
+
+	trace, err := nodes[1].TraceRoute(ctx, &pb.TraceRequest{TargetId: &pb.NodeID{Bytes: nodes[1].ID.Bytes()}})
+	if err != nil {
+		t.Fatalf("TraceRoute failed: %v", err)
+	}
+	replayed := trace.Hops[0].Node
+	if _, err := nodes[0].Publish(ctx, &pb.PublishRequest{ObjectId: &pb.NodeID{Bytes: objID.Bytes()}, Publisher: replayed}); err == nil {
+		t.Errorf("Publish on behalf of another node without its signature was accepted")
+	}
+
+	if _, ok := nodes[0].LocationPointers[objID]; ok {
+		t.Errorf("A forged publish left a location pointer")
+	}
+}
+
+func TestConnectionFailureUpdatesRoutingTable(t *testing.T) {
+	nodes := createCluster(t, 3)
+	defer stopCluster(nodes[:2])
+
+	gone := nodes[2]
+	nodes[0].Table.Add(node.Neighbor{ID: gone.ID, Address: gone.Address})
+	gone.Stop()
+
+	if _, err := nodes[0].Probe(gone.Address); err == nil {
+		t.Fatalf("Probe of a stopped node succeeded")
+	}
+
+	inTable := func() bool {
+		for level := 0; level < id.DIGITS; level++ {
+			for _, nb := range nodes[0].Table.GetLevel(level) {
+				if nb.ID.Equals(gone.ID) {
+					return true
+				}
+			}
+		}
+		return false
+	}
+	for deadline := time.Now().Add(3 * time.Second); time.Now().Before(deadline) && !nodes[0].Table.IsSuspect(gone.ID); {
+		time.Sleep(50 * time.Millisecond)
+	}
+	if !nodes[0].Table.IsSuspect(gone.ID) {
+		t.Errorf("Failed connection did not mark the neighbor suspect")
+	}
+	// Only the keepalive decides whether the neighbor is gone for good.
+	if !inTable() {
+		t.Errorf("Failed connection removed the neighbor from the routing table")
+	}
+}
+
+func TestGetDeadlineWithHungPeer(t *testing.T) {
+	nodes := createCluster(t, 3)
+	defer stopCluster(nodes)
+
+	port := getNextPort()
+	hung, err := node.NewNodeWithConfig(node.Config{Port: port, Placement: localPlacement{}})
+	if err != nil {
+		t.Fatalf("Failed to create node: %v", err)
+	}
+	go hung.Start()
+	joinNode(t, hung, nodes[0].Address)
+	if err := hung.StoreAndPublish("only-copy", []byte("unreachable")); err != nil {
+		t.Fatalf("Publish failed: %v", err)
+	}
+	time.Sleep(500 * time.Millisecond)
+
+	// The peer keeps accepting connections but never answers.
+	hung.Stop()
+	blackhole, err := net.Listen("tcp", fmt.Sprintf(":%d", port))
+	if err != nil {
+		t.Fatalf("Failed to take over port %d: %v", port, err)
+	}
+	defer blackhole.Close()
+	go func() {
+		for {
+			conn, err := blackhole.Accept()
+			if err != nil {
+				return
+			}
+			defer conn.Close()
+		}
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	start := time.Now()
+	_, err = nodes[0].GetWithOptions(ctx, "only-copy", node.ReadOptions{})
+	if elapsed := time.Since(start); elapsed > 3*time.Second {
+		t.Errorf("Get ignored its deadline and took %v", elapsed)
+	}
+	// Routes through the peer fail over, and a fetch from it runs out the
+	// deadline; either way the only copy is out of reach.
+	if err == nil {
+		t.Errorf("Expected the get to fail")
+	}
+}
+
+func TestRoutingFailsOverToBackup(t *testing.T) {
+	nodes := createCluster(t, 3)
+	defer stopCluster(nodes)
+
+	// A dead neighbor sharing nodes[1]'s slot, closer than nodes[1], becomes
+	// the primary next hop for its own ID.
+	backup := nodes[1]
+	level := id.SharedPrefixLength(nodes[0].ID, backup.ID)
+	last := id.DIGITS - 1
+	deadID := backup.ID.SetDigit(last, (backup.ID.GetDigit(last)+1)%id.RADIX)
+
+	l, err := net.Listen("tcp", "localhost:0")
+	if err != nil {
+		t.Fatalf("Failed to reserve a port: %v", err)
+	}
+	deadAddr := l.Addr().String()
+	l.Close()
+
+	nodes[0].Table.Add(node.Neighbor{ID: backup.ID, Address: backup.Address, Latency: time.Second})
+	nodes[0].Table.Add(node.Neighbor{ID: deadID, Address: deadAddr})
+	if primary := nodes[0].Table.Get(level, deadID.GetDigit(level)); len(primary) == 0 || !primary[0].ID.Equals(deadID) {
+		t.Fatalf("Dead neighbor is not the primary of its slot")
+	}
+
+	ctx := context.Background()
+	if _, err := nodes[0].Lookup(ctx, &pb.LookupRequest{ObjectId: &pb.NodeID{Bytes: deadID.Bytes()}}); err != nil {
+		t.Fatalf("Lookup did not fail over: %v", err)
+	}
+	if failovers := nodes[0].MetricsSnapshot().RouteFailovers; failovers == 0 {
+		t.Errorf("Failover was not counted")
+	}
+
+	resp, err := nodes[0].GetNextHop(ctx, &pb.RouteRequest{TargetId: &pb.NodeID{Bytes: deadID.Bytes()}})
+	if err != nil {
+		t.Fatalf("GetNextHop failed: %v", err)
+	}
+	if bytes.Equal(resp.NextHop.Id.Bytes, deadID.Bytes()) {
+		t.Errorf("Routing still prefers the failed neighbor")
+	}
+}
+
+func TestRoutingFailsOverPastHungHop(t *testing.T) {
+	nodes := createCluster(t, 3)
+	defer stopCluster(nodes)
+
+	// A neighbor that accepts connections but never answers takes the slot
+	// of nodes[1], as in TestRoutingFailsOverToBackup.
+	backup := nodes[1]
+	level := id.SharedPrefixLength(nodes[0].ID, backup.ID)
+	last := id.DIGITS - 1
+	hungID := backup.ID.SetDigit(last, (backup.ID.GetDigit(last)+1)%id.RADIX)
+
+	blackhole, err := net.Listen("tcp", "localhost:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	defer blackhole.Close()
+	go func() {
+		for {
+			conn, err := blackhole.Accept()
+			if err != nil {
+				return
+			}
+			defer conn.Close()
+		}
+	}()
+
+	nodes[0].Table.Add(node.Neighbor{ID: backup.ID, Address: backup.Address, Latency: time.Second})
+	nodes[0].Table.Add(node.Neighbor{ID: hungID, Address: blackhole.Addr().String()})
+	if primary := nodes[0].Table.Get(level, hungID.GetDigit(level)); len(primary) == 0 || !primary[0].ID.Equals(hungID) {
+		t.Fatalf("Hung neighbor is not the primary of its slot")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 4*time.Second)
+	defer cancel()
+	if _, err := nodes[0].Lookup(ctx, &pb.LookupRequest{ObjectId: &pb.NodeID{Bytes: hungID.Bytes()}}); err != nil {
+		t.Fatalf("Lookup did not fail over past the hung hop: %v", err)
+	}
+	if !nodes[0].Table.IsSuspect(hungID) {
+		t.Errorf("Hung neighbor was not marked suspect")
+	}
+}